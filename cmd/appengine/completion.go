@@ -0,0 +1,116 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appengine
+
+import (
+	"github.com/astarte-platform/astarte-go/client"
+	"github.com/astarte-platform/astartectl/utils"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	for _, cmd := range []*cobra.Command{devicesDataSnapshotCmd, devicesGetSamplesCmd, devicesSendDataCmd,
+		devicesPublishDatastreamCmd, devicesSetPropertyCmd, devicesUnSetPropertyCmd} {
+		cmd.ValidArgsFunction = utils.CompleteArgs(completeDevices, completeDeviceInterfaces)
+	}
+	for _, cmd := range []*cobra.Command{devicesShowCmd, attributesListCmd, attributeSetCmd, attributeRemoveCmd} {
+		cmd.ValidArgsFunction = utils.CompleteArgs(completeDevices)
+	}
+	for _, cmd := range []*cobra.Command{aliasesListCmd, aliasesAddCmd, aliasesRemoveCmd} {
+		cmd.ValidArgsFunction = utils.CompleteArgs(completeDeviceIDs)
+	}
+	devicesCredentialsInhibitCmd.ValidArgsFunction = utils.CompleteArgs(completeDevices, completeBooleans)
+	groupsDevicesListCmd.ValidArgsFunction = utils.CompleteArgs(completeGroups)
+	groupsDevicesAddCmd.ValidArgsFunction = utils.CompleteArgs(completeGroups, completeDevices)
+	groupsDevicesRemoveCmd.ValidArgsFunction = utils.CompleteArgs(completeGroups, completeGroupDevices)
+}
+
+// completeDevices returns the Device IDs and the aliases of the devices in the realm
+func completeDevices(args []string) ([]string, error) {
+	return listCompletedDevices(true)
+}
+
+// completeDeviceIDs returns the Device IDs of the devices in the realm
+func completeDeviceIDs(args []string) ([]string, error) {
+	return listCompletedDevices(false)
+}
+
+func listCompletedDevices(withAliases bool) ([]string, error) {
+	paginator, err := astarteAPIClient.GetDeviceListPaginator(realm, 100, client.DeviceDetailsFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := []string{}
+	for devices := 0; paginator.HasNextPage() && devices < utils.MaxCompletedDevices; {
+		nextPageCall, err := paginator.GetNextPage()
+		if err != nil {
+			return nil, err
+		}
+		deviceListRes, err := nextPageCall.Run(astarteAPIClient)
+		if err != nil {
+			return nil, err
+		}
+		rawPage, err := deviceListRes.Parse()
+		if err != nil {
+			return nil, err
+		}
+		page, _ := rawPage.([]client.DeviceDetails)
+		for _, deviceDetails := range page {
+			candidates = append(candidates, deviceDetails.DeviceID)
+			if !withAliases {
+				continue
+			}
+			for _, alias := range deviceDetails.Aliases {
+				candidates = append(candidates, alias)
+			}
+		}
+		devices += len(page)
+	}
+	return candidates, nil
+}
+
+func completeDeviceInterfaces(args []string) ([]string, error) {
+	return utils.RequestCandidates(astarteAPIClient)(astarteAPIClient.ListDeviceInterfaces(realm, args[0], client.AutodiscoverDeviceIdentifier))
+}
+
+func completeBooleans(args []string) ([]string, error) {
+	return []string{"true", "false"}, nil
+}
+
+func completeGroups(args []string) ([]string, error) {
+	return utils.RequestCandidates(astarteAPIClient)(astarteAPIClient.ListGroups(realm))
+}
+
+func completeGroupDevices(args []string) ([]string, error) {
+	paginator, err := astarteAPIClient.ListGroupDevices(realm, args[0], 100, client.DeviceIDFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := []string{}
+	for paginator.HasNextPage() && len(candidates) < utils.MaxCompletedDevices {
+		nextPageCall, err := paginator.GetNextPage()
+		if err != nil {
+			return nil, err
+		}
+		page, err := utils.RequestCandidates(astarteAPIClient)(nextPageCall, nil)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, page...)
+	}
+	return candidates, nil
+}
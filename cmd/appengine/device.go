@@ -32,6 +32,7 @@ import (
 	"github.com/astarte-platform/astarte-go/auth"
 	"github.com/astarte-platform/astarte-go/client"
 	"github.com/astarte-platform/astarte-go/interfaces"
+	"github.com/astarte-platform/astartectl/printer"
 	"github.com/astarte-platform/astartectl/utils"
 	"github.com/jedib0t/go-pretty/table"
 
@@ -186,8 +187,6 @@ this is automatically determined - however, you can tweak this behavior by using
 	RunE:    devicesUnSetPropertyF,
 }
 
-var supportedOutputTypes = []string{"default", "csv", "json"}
-
 // DeviceFilterType represents the possible filter types for the device list
 type DeviceFilterType string
 
@@ -210,15 +209,6 @@ func (f DeviceFilterType) IsValid() error {
 	return errors.New("invalid filter type")
 }
 
-func isASupportedOutputType(outputType string) bool {
-	for _, s := range supportedOutputTypes {
-		if s == outputType {
-			return true
-		}
-	}
-	return false
-}
-
 func init() {
 	AppEngineCmd.AddCommand(devicesCmd)
 
@@ -235,12 +225,10 @@ connected: allows filtering devices that are currently connected/disconnected. I
 	devicesGetSamplesCmd.Flags().Bool("ascending", false, "When set, returns samples in ascending order rather than descending.")
 	devicesGetSamplesCmd.Flags().String("since", "", "When set, returns only samples newer than the provided date.")
 	devicesGetSamplesCmd.Flags().String("to", "", "When set, returns only samples older than the provided date.")
-	devicesGetSamplesCmd.Flags().StringP("output", "o", "default", "The type of output (default,csv,json)")
 	devicesGetSamplesCmd.Flags().String("force-id-type", "", "When set, rather than autodetecting, it forces the device ID to be evaluated as a (device-id,alias).")
 	devicesGetSamplesCmd.Flags().Bool("aggregate", false, "When set, if Realm Management checks are disabled, it forces resolution of the interface as an aggregate datastream.")
 	devicesGetSamplesCmd.Flags().Bool("skip-realm-management-checks", false, "When set, it skips any consistency checks on Realm Management before performing the Query. This might lead to unexpected errors.")
 
-	devicesDataSnapshotCmd.Flags().StringP("output", "o", "default", "The type of output (default,csv,json)")
 	devicesDataSnapshotCmd.Flags().String("force-id-type", "", "When set, rather than autodetecting, it forces the device ID to be evaluated as a (device-id,alias).")
 	devicesDataSnapshotCmd.Flags().Bool("skip-realm-management-checks", false, "When set, it skips any consistency checks on Realm Management before performing the Query. This might lead to unexpected errors. This has effect only if data-snapshot is invoked for a specific interface.")
 	devicesDataSnapshotCmd.Flags().String("interface-type", "", "When set, if Realm Management checks are disabled, it forces resolution of the interface as the specified type. Valid options are: properties, individual-datastream, aggregate-datastream, individual-parametric-datastream, aggregate-parametric-datastream.")
@@ -294,16 +282,12 @@ func devicesListF(command *cobra.Command, args []string) error {
 	}
 
 	if !details && len(deviceFiltersMap) == 0 {
-		printSimpleDevicesList(realm)
-	} else {
-		printDevicesList(realm, details, deviceFiltersMap)
-
+		return printSimpleDevicesList(realm)
 	}
-
-	return nil
+	return printDevicesList(realm, details, deviceFiltersMap)
 }
 
-func printSimpleDevicesList(realm string) {
+func printSimpleDevicesList(realm string) error {
 	paginator, err := astarteAPIClient.GetDeviceListPaginator(realm, 100, client.DeviceIDFormat)
 	if err != nil {
 		fmt.Println(err)
@@ -333,10 +317,10 @@ func printSimpleDevicesList(realm string) {
 		deviceIDList = append(deviceIDList, page...)
 	}
 
-	fmt.Println(deviceIDList)
+	return printer.PrintList("Device ID", deviceIDList)
 }
 
-func printDevicesList(realm string, details bool, deviceFilters map[DeviceFilterType]interface{}) {
+func printDevicesList(realm string, details bool, deviceFilters map[DeviceFilterType]interface{}) error {
 	paginator, err := astarteAPIClient.GetDeviceListPaginator(realm, 100, client.DeviceDetailsFormat)
 	if err != nil {
 		fmt.Println(err)
@@ -345,6 +329,8 @@ func printDevicesList(realm string, details bool, deviceFilters map[DeviceFilter
 
 	// This will be used only if details is false
 	deviceIDList := []string{}
+	// This will be used only if details is true and a structured output was requested
+	deviceDetailsList := []client.DeviceDetails{}
 
 	hasFilters := len(deviceFilters) > 0
 
@@ -371,11 +357,14 @@ func printDevicesList(realm string, details bool, deviceFilters map[DeviceFilter
 				continue
 			}
 
-			if details {
-				// If we want details, we print the list as we go
+			switch {
+			case details && printer.IsStructured():
+				deviceDetailsList = append(deviceDetailsList, deviceDetails)
+			case details:
+				// If we want human readable details, we print the list as we go
 				prettyPrintDeviceDetails(deviceDetails)
 				fmt.Println()
-			} else {
+			default:
 				// Otherwise, we populate the deviceIDList
 				deviceIDList = append(deviceIDList, deviceDetails.DeviceID)
 			}
@@ -383,8 +372,12 @@ func printDevicesList(realm string, details bool, deviceFilters map[DeviceFilter
 	}
 
 	if !details {
-		fmt.Println(deviceIDList)
+		return printer.PrintList("Device ID", deviceIDList)
+	}
+	if printer.IsStructured() {
+		return printer.Print(deviceDetailsList, func() {})
 	}
+	return nil
 }
 
 func deviceShouldBeIncluded(device client.DeviceDetails, deviceFilters map[DeviceFilterType]interface{}) bool {
@@ -465,7 +458,7 @@ func buildDeviceFilters(rawDeviceFilters []string) (map[DeviceFilterType]interfa
 }
 
 func prettyPrintDeviceDetails(deviceDetails client.DeviceDetails) {
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+	w := tabwriter.NewWriter(printer.Out, 0, 0, 4, ' ', 0)
 	if deviceDetails.CredentialsInhibited {
 		fmt.Fprintf(w, "Credentials Inhibited:\t%v\n", deviceDetails.CredentialsInhibited)
 	}
@@ -534,8 +527,7 @@ func devicesShowF(command *cobra.Command, args []string) error {
 		os.Exit(1)
 	}
 
-	prettyPrintDeviceDetails(deviceDetails)
-	return nil
+	return printer.Print(deviceDetails, func() { prettyPrintDeviceDetails(deviceDetails) })
 }
 
 func deviceDetails(realm, deviceID string, deviceIdentifierType client.DeviceIdentifierType) (client.DeviceDetails, error) {
@@ -556,21 +548,6 @@ func deviceDetails(realm, deviceID string, deviceIdentifierType client.DeviceIde
 	return deviceDetails, nil
 }
 
-func tableWriterForOutputType(outputType string) table.Writer {
-	t := table.NewWriter()
-	switch outputType {
-	case "default":
-		t.SetOutputMirror(os.Stdout)
-		t.SetStyle(table.StyleLight)
-	case "csv":
-		t.SetOutputMirror(os.Stdout)
-	case "json":
-	default:
-		return nil
-	}
-	return t
-}
-
 func devicesDataSnapshotF(command *cobra.Command, args []string) error {
 	if utils.ShouldCurl() {
 		fmt.Println(dataSnapshotCurl)
@@ -606,18 +583,14 @@ func devicesDataSnapshotF(command *cobra.Command, args []string) error {
 		return fmt.Errorf("When not using Realm Management checks, --interface-type should always be specified")
 	}
 
-	outputType, err := command.Flags().GetString("output")
-	if err != nil {
+	if err := printer.Validate(); err != nil {
 		return err
 	}
-	if !isASupportedOutputType(outputType) {
-		return fmt.Errorf("%v is not a supported output type. Supported output types are %v", outputType, supportedOutputTypes)
-	}
 
 	interfacesToFetch := []interfaces.AstarteInterface{}
 
 	// Go with the table header
-	t := tableWriterForOutputType(outputType)
+	t := printer.NewTable()
 
 	// Distinguish here whether we're doing a full snapshot or just a single-interface snapshot, and act accordingly
 	if snapshotInterface == "" {
@@ -675,7 +648,7 @@ func devicesDataSnapshotF(command *cobra.Command, args []string) error {
 				rawVal, _ := snapshotRes.Parse()
 				val, _ := rawVal.(map[string]client.DatastreamObjectValue)
 				for path, aggregate := range val {
-					if outputType == "json" {
+					if printer.IsStructured() {
 						jsonOutput[i.Name] = val
 					} else {
 						for _, k := range aggregate.Values.Keys() {
@@ -686,10 +659,10 @@ func devicesDataSnapshotF(command *cobra.Command, args []string) error {
 							}
 							if snapshotInterface == "" {
 								t.AppendRow([]interface{}{i.Name, fmt.Sprintf("%s/%s", path, k), v, i.Ownership,
-									timestampForOutput(aggregate.Timestamp, outputType)})
+									timestampForOutput(aggregate.Timestamp)})
 							} else {
 								t.AppendRow([]interface{}{i.Name, fmt.Sprintf("%s/%s", path, k), v,
-									timestampForOutput(aggregate.Timestamp, outputType)})
+									timestampForOutput(aggregate.Timestamp)})
 							}
 						}
 					}
@@ -713,10 +686,10 @@ func devicesDataSnapshotF(command *cobra.Command, args []string) error {
 					jsonRepresentation[k] = v
 					if snapshotInterface == "" {
 						t.AppendRow([]interface{}{i.Name, k, item.Value, i.Ownership,
-							timestampForOutput(item.Timestamp, outputType)})
+							timestampForOutput(item.Timestamp)})
 					} else {
 						t.AppendRow([]interface{}{i.Name, k, item.Value,
-							timestampForOutput(item.Timestamp, outputType)})
+							timestampForOutput(item.Timestamp)})
 					}
 				}
 				jsonOutput[i.Name] = jsonRepresentation
@@ -750,9 +723,7 @@ func devicesDataSnapshotF(command *cobra.Command, args []string) error {
 	}
 
 	// Done
-	renderOutput(t, jsonOutput, outputType)
-
-	return nil
+	return printer.PrintTable(t, jsonOutput)
 }
 
 func warnOrFail(snapshotInterface, interfaceName string, err error) {
@@ -829,13 +800,9 @@ func devicesGetSamplesF(command *cobra.Command, args []string) error {
 			return err
 		}
 	}
-	outputType, err := command.Flags().GetString("output")
-	if err != nil {
+	if err := printer.Validate(); err != nil {
 		return err
 	}
-	if !isASupportedOutputType(outputType) {
-		return fmt.Errorf("%v is not a supported output type. Supported output types are %v", outputType, supportedOutputTypes)
-	}
 
 	var isAggregate bool
 	if !skipRealmManagementChecks {
@@ -892,7 +859,7 @@ func devicesGetSamplesF(command *cobra.Command, args []string) error {
 	mapAcc := map[string]any{}
 
 	// We are good to go.
-	t := tableWriterForOutputType(outputType)
+	t := printer.NewTable()
 	if !isAggregate {
 		printedValues := 0
 		datastreamPaginator, err := astarteAPIClient.GetDatastreamIndividualTimeWindowPaginator(realm, deviceID,
@@ -926,11 +893,11 @@ func devicesGetSamplesF(command *cobra.Command, args []string) error {
 
 				// and start appending values
 				for _, v := range page {
-					if outputType != "json" {
+					if !printer.IsStructured() {
 						if v.Value != nil {
-							t.AppendRow([]interface{}{timestampForOutput(v.Timestamp, outputType), v.Value})
+							t.AppendRow([]interface{}{timestampForOutput(v.Timestamp), v.Value})
 						} else {
-							t.AppendRow([]interface{}{timestampForOutput(v.Timestamp, outputType), []string{}})
+							t.AppendRow([]interface{}{timestampForOutput(v.Timestamp), []string{}})
 						}
 
 					} else {
@@ -938,11 +905,12 @@ func devicesGetSamplesF(command *cobra.Command, args []string) error {
 					}
 					printedValues++
 					if printedValues >= limit && limit > 0 {
-						renderOutput(t, sliceAcc, outputType)
-						return nil
+						return printer.PrintTable(t, sliceAcc)
 					}
 				}
-				renderOutput(t, sliceAcc, outputType)
+				if err := printer.PrintTable(t, sliceAcc); err != nil {
+					return err
+				}
 
 			case map[string]client.DatastreamIndividualValue:
 				// Go with the table header regardless of the requested output type
@@ -951,11 +919,11 @@ func devicesGetSamplesF(command *cobra.Command, args []string) error {
 				// and start appending values
 
 				for k, v := range page {
-					if outputType != "json" {
+					if !printer.IsStructured() {
 						if v.Value != nil {
-							t.AppendRow([]interface{}{k, timestampForOutput(v.Timestamp, outputType), v.Value})
+							t.AppendRow([]interface{}{k, timestampForOutput(v.Timestamp), v.Value})
 						} else {
-							t.AppendRow([]interface{}{k, timestampForOutput(v.Timestamp, outputType), []string{}})
+							t.AppendRow([]interface{}{k, timestampForOutput(v.Timestamp), []string{}})
 						}
 
 					} else {
@@ -963,11 +931,12 @@ func devicesGetSamplesF(command *cobra.Command, args []string) error {
 					}
 					printedValues++
 					if printedValues >= limit && limit > 0 {
-						renderOutput(t, mapAcc, outputType)
-						return nil
+						return printer.PrintTable(t, mapAcc)
 					}
 				}
-				renderOutput(t, mapAcc, outputType)
+				if err := printer.PrintTable(t, mapAcc); err != nil {
+					return err
+				}
 			}
 		}
 	} else {
@@ -1001,10 +970,10 @@ func devicesGetSamplesF(command *cobra.Command, args []string) error {
 				headerPrinted := false
 
 				for _, v := range page {
-					if outputType != "json" {
+					if !printer.IsStructured() {
 						// Iterate the aggregate
 						line := []interface{}{}
-						line = append(line, timestampForOutput(v.Timestamp, outputType))
+						line = append(line, timestampForOutput(v.Timestamp))
 						for _, path := range v.Values.Keys() {
 							value, _ := v.Values.Get(path)
 							if !headerPrinted {
@@ -1026,11 +995,12 @@ func devicesGetSamplesF(command *cobra.Command, args []string) error {
 					}
 					printedValues++
 					if printedValues >= limit && limit > 0 {
-						renderOutput(t, sliceAcc, outputType)
-						return nil
+						return printer.PrintTable(t, sliceAcc)
 					}
 				}
-				renderOutput(t, sliceAcc, outputType)
+				if err := printer.PrintTable(t, sliceAcc); err != nil {
+					return err
+				}
 
 			case map[string][]client.DatastreamObjectValue:
 				headerRow := table.Row{"Base path", "Timestamp"}
@@ -1039,7 +1009,7 @@ func devicesGetSamplesF(command *cobra.Command, args []string) error {
 				keys := []string{}
 				for k, v := range page {
 					for _, item := range v {
-						if outputType != "json" {
+						if !printer.IsStructured() {
 							line := []interface{}{}
 							if !headerPrinted {
 								for _, path := range item.Values.Keys() {
@@ -1050,7 +1020,7 @@ func devicesGetSamplesF(command *cobra.Command, args []string) error {
 								headerPrinted = true
 							}
 							line = append(line, k)
-							line = append(line, timestampForOutput(item.Timestamp, outputType))
+							line = append(line, timestampForOutput(item.Timestamp))
 							for _, key := range keys {
 								value, _ := item.Values.Get(key)
 								if value != nil {
@@ -1065,12 +1035,13 @@ func devicesGetSamplesF(command *cobra.Command, args []string) error {
 						}
 						printedValues++
 						if printedValues >= limit && limit > 0 {
-							renderOutput(t, mapAcc, outputType)
-							return nil
+							return printer.PrintTable(t, mapAcc)
 						}
 					}
 				}
-				renderOutput(t, mapAcc, outputType)
+				if err := printer.PrintTable(t, mapAcc); err != nil {
+					return err
+				}
 			}
 		}
 	}
@@ -1319,6 +1290,8 @@ func devicesPublishDataStreamF(command *cobra.Command, args []string) error {
 		os.Exit(1)
 	}
 
+	utils.MaybeDryRunAndExit(sendDataCall, astarteAPIClient)
+
 	sendDataRes, err := sendDataCall.Run(astarteAPIClient)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -1421,6 +1394,8 @@ func devicesSetPropertyF(command *cobra.Command, args []string) error {
 		os.Exit(1)
 	}
 
+	utils.MaybeDryRunAndExit(sendDataCall, astarteAPIClient)
+
 	sendDataRes, err := sendDataCall.Run(astarteAPIClient)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -1484,6 +1459,8 @@ func devicesUnSetPropertyF(command *cobra.Command, args []string) error {
 		os.Exit(1)
 	}
 
+	utils.MaybeDryRunAndExit(sendDataCall, astarteAPIClient)
+
 	sendDataRes, err := sendDataCall.Run(astarteAPIClient)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -1591,30 +1568,17 @@ func getProtoInterface(deviceID string, deviceIdentifierType client.DeviceIdenti
 	return iface, nil
 }
 
-func timestampForOutput(timestamp time.Time, outputType string) string {
-	switch outputType {
-	case "default":
+func timestampForOutput(timestamp time.Time) string {
+	switch printer.CurrentFormat() {
+	case printer.TableFormat:
 		return timestamp.String()
-	case "csv":
+	case printer.CSVFormat:
 		return timestamp.Format(time.RFC3339Nano)
-	case "json":
 	}
 
 	return ""
 }
 
-func renderOutput(t table.Writer, accumulator interface{}, outputType string) {
-	switch outputType {
-	case "default":
-		t.Render()
-	case "csv":
-		t.RenderCSV()
-	case "json":
-		marshaledOutput, _ := json.MarshalIndent(accumulator, "", "    ")
-		fmt.Println(string(marshaledOutput))
-	}
-}
-
 func parseSendDataPayload(payload string, mappingType interfaces.AstarteMappingType) (interface{}, error) {
 	// Default to string, as it will be ok for most cases
 	var ret interface{} = payload
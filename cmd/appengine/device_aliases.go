@@ -21,6 +21,7 @@ import (
 
 	"github.com/astarte-platform/astarte-go/client"
 	"github.com/astarte-platform/astarte-go/deviceid"
+	"github.com/astarte-platform/astartectl/printer"
 	"github.com/astarte-platform/astartectl/utils"
 	"github.com/spf13/cobra"
 )
@@ -91,10 +92,10 @@ func aliasesListF(command *cobra.Command, args []string) error {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
-	aliases, _ := deviceAliasesRes.Parse()
+	rawAliases, _ := deviceAliasesRes.Parse()
+	aliases, _ := rawAliases.([]string)
 
-	fmt.Printf("%v\n", aliases)
-	return nil
+	return printer.PrintList("Alias", aliases)
 }
 
 func aliasesAddF(command *cobra.Command, args []string) error {
@@ -116,6 +117,8 @@ func aliasesAddF(command *cobra.Command, args []string) error {
 		os.Exit(1)
 	}
 
+	utils.MaybeDryRunAndExit(addDeviceCall, astarteAPIClient)
+
 	addDeviceRes, err := addDeviceCall.Run(astarteAPIClient)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -143,6 +146,7 @@ func aliasesRemoveF(command *cobra.Command, args []string) error {
 	}
 
 	utils.MaybeCurlAndExit(deleteAliasCall, astarteAPIClient)
+	utils.MaybeDryRunAndExit(deleteAliasCall, astarteAPIClient)
 
 	deleteAliasRes, err := deleteAliasCall.Run(astarteAPIClient)
 	if err != nil {
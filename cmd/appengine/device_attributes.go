@@ -17,9 +17,12 @@ package appengine
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 
+	"github.com/astarte-platform/astartectl/printer"
 	"github.com/astarte-platform/astartectl/utils"
+	"github.com/jedib0t/go-pretty/table"
 	"github.com/spf13/cobra"
 )
 
@@ -110,10 +113,21 @@ func attributesListF(command *cobra.Command, args []string) error {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
-	attributes, _ := attributesRes.Parse()
+	rawAttributes, _ := attributesRes.Parse()
+	attributes, _ := rawAttributes.(map[string]string)
 
-	fmt.Printf("%v\n", attributes)
-	return nil
+	keys := make([]string, 0, len(attributes))
+	for k := range attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	t := printer.NewTable()
+	t.AppendHeader(table.Row{"Key", "Value"})
+	for _, k := range keys {
+		t.AppendRow(table.Row{k, attributes[k]})
+	}
+	return printer.PrintTable(t, attributes)
 }
 
 func attributeSetF(command *cobra.Command, args []string) error {
@@ -141,6 +155,7 @@ func attributeSetF(command *cobra.Command, args []string) error {
 	}
 
 	utils.MaybeCurlAndExit(setAttributeCall, astarteAPIClient)
+	utils.MaybeDryRunAndExit(setAttributeCall, astarteAPIClient)
 
 	setAttributeRes, err := setAttributeCall.Run(astarteAPIClient)
 	if err != nil {
@@ -174,6 +189,7 @@ func attributeRemoveF(command *cobra.Command, args []string) error {
 	}
 
 	utils.MaybeCurlAndExit(deleteAttributeCall, astarteAPIClient)
+	utils.MaybeDryRunAndExit(deleteAttributeCall, astarteAPIClient)
 
 	deleteAttributeRes, err := deleteAttributeCall.Run(astarteAPIClient)
 	if err != nil {
@@ -77,6 +77,7 @@ func devicesCredentialsInhibitF(command *cobra.Command, args []string) error {
 	}
 
 	utils.MaybeCurlAndExit(inhibitDeviceReq, astarteAPIClient)
+	utils.MaybeDryRunAndExit(inhibitDeviceReq, astarteAPIClient)
 
 	inhibitDeviceRes, err := inhibitDeviceReq.Run(astarteAPIClient)
 	if err != nil {
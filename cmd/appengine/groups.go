@@ -20,6 +20,7 @@ import (
 	"strings"
 
 	"github.com/astarte-platform/astarte-go/client"
+	"github.com/astarte-platform/astartectl/printer"
 	"github.com/astarte-platform/astartectl/utils"
 	"github.com/spf13/cobra"
 )
@@ -125,10 +126,10 @@ func groupsListF(command *cobra.Command, args []string) error {
 		os.Exit(1)
 	}
 
-	groupsList, _ := groupsListRes.Parse()
+	rawGroupsList, _ := groupsListRes.Parse()
+	groupsList, _ := rawGroupsList.([]string)
 
-	fmt.Println(groupsList)
-	return nil
+	return printer.PrintList("Group", groupsList)
 }
 
 func groupsCreateF(command *cobra.Command, args []string) error {
@@ -167,6 +168,7 @@ func groupsCreateF(command *cobra.Command, args []string) error {
 	}
 
 	utils.MaybeCurlAndExit(createGroupCall, astarteAPIClient)
+	utils.MaybeDryRunAndExit(createGroupCall, astarteAPIClient)
 
 	createGroupRes, err := createGroupCall.Run(astarteAPIClient)
 	if err != nil {
@@ -209,8 +211,7 @@ func groupsDevicesListF(command *cobra.Command, args []string) error {
 		deviceList = append(deviceList, devices...)
 	}
 
-	fmt.Println(deviceList)
-	return nil
+	return printer.PrintList("Device ID", deviceList)
 }
 
 func groupsDevicesAddF(command *cobra.Command, args []string) error {
@@ -227,6 +228,7 @@ func groupsDevicesAddF(command *cobra.Command, args []string) error {
 	}
 
 	utils.MaybeCurlAndExit(addDeviceCall, astarteAPIClient)
+	utils.MaybeDryRunAndExit(addDeviceCall, astarteAPIClient)
 
 	addDeviceRes, err := addDeviceCall.Run(astarteAPIClient)
 	if err != nil {
@@ -254,6 +256,7 @@ func groupsDevicesRemoveF(command *cobra.Command, args []string) error {
 	}
 
 	utils.MaybeCurlAndExit(removeDeviceCall, astarteAPIClient)
+	utils.MaybeDryRunAndExit(removeDeviceCall, astarteAPIClient)
 
 	removeDeviceRes, err := removeDeviceCall.Run(astarteAPIClient)
 	if err != nil {
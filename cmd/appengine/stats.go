@@ -15,9 +15,10 @@
 package appengine
 
 import (
-	"fmt"
-
+	"github.com/astarte-platform/astarte-go/client"
+	"github.com/astarte-platform/astartectl/printer"
 	"github.com/astarte-platform/astartectl/utils"
+	"github.com/jedib0t/go-pretty/table"
 	"github.com/spf13/cobra"
 )
 
@@ -57,8 +58,11 @@ func statsDevicesF(command *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	devicesStats, _ := devicesStatsRes.Parse()
+	rawDevicesStats, _ := devicesStatsRes.Parse()
+	devicesStats, _ := rawDevicesStats.(client.DevicesStats)
 
-	fmt.Printf("%+v\n", devicesStats)
-	return nil
+	t := printer.NewTable()
+	t.AppendHeader(table.Row{"Total Devices", "Connected Devices"})
+	t.AppendRow(table.Row{devicesStats.TotalDevices, devicesStats.ConnectedDevices})
+	return printer.PrintTable(t, devicesStats)
 }
@@ -0,0 +1,169 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apply
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/astarte-platform/astarte-go/astarteservices"
+	"github.com/astarte-platform/astarte-go/client"
+	"github.com/astarte-platform/astartectl/manifest"
+	"github.com/astarte-platform/astartectl/printer"
+	"github.com/astarte-platform/astartectl/utils"
+	"github.com/jedib0t/go-pretty/table"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// ApplyCmd represents the apply command
+var ApplyCmd = &cobra.Command{
+	Use:   "apply -f <manifest>...",
+	Short: "Apply declarative manifests to a realm",
+	Long: `Bring a realm to the state described by one or more manifests.
+
+Manifests are YAML or JSON files, possibly containing multiple documents separated by ---.
+Each document is either a bare interface, trigger or trigger delivery policy, or an envelope
+such as:
+
+  kind: Group
+  spec:
+    group_name: mygroup
+    devices:
+      - 2TBn-jNESuuHamE2Zo1anA
+
+Supported kinds are Interface, Trigger, TriggerDeliveryPolicy and Group. Directories are
+walked recursively, and - reads from stdin.
+
+The plan is computed against the live realm and shown before applying it. Resources are
+applied in dependency order: trigger delivery policies and interfaces are installed before
+the triggers using them. Nothing is ever deleted from the realm: triggers and trigger delivery
+policies which differ from the manifest are replaced only when --force is given.
+With --dry-run, only the plan is shown, like with --plan.`,
+	Example: `  astartectl apply -f realm-manifests/
+  astartectl apply -f interfaces.yaml -f triggers.yaml --plan`,
+	Args:              cobra.NoArgs,
+	PersistentPreRunE: applyPersistentPreRunE,
+	RunE:              applyF,
+}
+
+var realm string
+var astarteAPIClient *client.Client
+
+func init() {
+	ApplyCmd.Flags().StringSliceP("filename", "f", nil, "Manifest file or directory to apply. Can be specified multiple times")
+	_ = ApplyCmd.MarkFlagRequired("filename")
+	ApplyCmd.Flags().Bool("plan", false, "Only show the plan, without applying it")
+	ApplyCmd.Flags().Bool("force", false, "Replace triggers and trigger delivery policies which differ from the manifest")
+	ApplyCmd.Flags().BoolP("non-interactive", "y", false, "Non-interactive mode. Will answer yes by default to all questions.")
+
+	ApplyCmd.Flags().StringP("realm-key", "k", "",
+		"Path to realm private key used to generate JWT for authentication")
+	_ = ApplyCmd.MarkFlagFilename("realm-key")
+	ApplyCmd.Flags().String("appengine-url", "",
+		"AppEngine API base URL. Defaults to <astarte-url>/appengine.")
+	ApplyCmd.Flags().String("realm-management-url", "",
+		"Realm Management API base URL. Defaults to <astarte-url>/realmmanagement.")
+	ApplyCmd.Flags().StringP("realm-name", "r", "",
+		"The name of the realm the manifests will be applied to")
+}
+
+func applyPersistentPreRunE(cmd *cobra.Command, args []string) error {
+	// Flags share their names with the ones of other commands, hence bind them only when running
+	_ = viper.BindPFlag("individual-urls.appengine", cmd.Flags().Lookup("appengine-url"))
+	_ = viper.BindPFlag("individual-urls.realm-management", cmd.Flags().Lookup("realm-management-url"))
+	_ = viper.BindPFlag("realm.key-file", cmd.Flags().Lookup("realm-key"))
+	individualURLVariables := map[astarteservices.AstarteService]string{
+		astarteservices.AppEngine:       "individual-urls.appengine",
+		astarteservices.RealmManagement: "individual-urls.realm-management",
+	}
+
+	var err error
+	astarteAPIClient, err = utils.APICommandSetup(individualURLVariables, "realm.key", "realm.key-file")
+	if err != nil {
+		return err
+	}
+
+	_ = viper.BindPFlag("realm.name", cmd.Flags().Lookup("realm-name"))
+	realm = viper.GetString("realm.name")
+	if realm == "" {
+		return errors.New("realm is required")
+	}
+
+	return nil
+}
+
+func applyF(command *cobra.Command, args []string) error {
+	paths, err := command.Flags().GetStringSlice("filename")
+	if err != nil {
+		return err
+	}
+	planOnly, err := command.Flags().GetBool("plan")
+	if err != nil {
+		return err
+	}
+	force, err := command.Flags().GetBool("force")
+	if err != nil {
+		return err
+	}
+	nonInteractive, err := command.Flags().GetBool("non-interactive")
+	if err != nil {
+		return err
+	}
+
+	resources, err := manifest.LoadPaths(paths)
+	if err != nil {
+		return err
+	}
+	if len(resources) == 0 {
+		return errors.New("no resources found in the given manifests")
+	}
+
+	plan, err := manifest.NewPlan(astarteAPIClient, realm, resources, manifest.Options{Force: force})
+	if err != nil {
+		return err
+	}
+	if err := printPlan(plan); err != nil {
+		return err
+	}
+
+	// On --dry-run the plan already describes every change which would be made
+	if planOnly || utils.IsDryRun() {
+		return nil
+	}
+	if !plan.HasChanges() {
+		fmt.Fprintln(os.Stderr, "Realm is up to date, nothing to apply.")
+		return nil
+	}
+	if !nonInteractive {
+		if ok, err := utils.AskForConfirmation(fmt.Sprintf("Will apply the plan to realm %s. Do you want to continue?", realm)); !ok || err != nil {
+			return err
+		}
+	}
+
+	// Progress goes to stderr, so that structured output stays parsable
+	return manifest.Apply(astarteAPIClient, plan, os.Stderr)
+}
+
+func printPlan(plan manifest.Plan) error {
+	t := printer.NewTable()
+	t.AppendHeader(table.Row{"Action", "Kind", "Name", "Details"})
+	for _, c := range plan.Changes {
+		t.AppendRow(table.Row{c.Action, c.Kind, c.Name, c.Details})
+	}
+	return printer.PrintTable(t, plan)
+}
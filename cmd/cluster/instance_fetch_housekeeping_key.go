@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/astarte-platform/astartectl/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -31,7 +32,7 @@ var fetchHKPrivateKeyCmd = &cobra.Command{
 }
 
 func init() {
-	fetchHKPrivateKeyCmd.PersistentFlags().StringP("output", "o", "", "When specified, saves the key to the specified file rather than printing it in stdout.")
+	utils.AddOutputFileFlag(fetchHKPrivateKeyCmd, "When specified, saves the key to the specified file rather than printing it in stdout.")
 
 	InstancesCmd.AddCommand(fetchHKPrivateKeyCmd)
 }
@@ -53,7 +54,7 @@ func fetchHKPrivateKeyF(command *cobra.Command, args []string) error {
 		os.Exit(1)
 	}
 
-	outputFile, err := command.Flags().GetString("output")
+	outputFile, err := utils.GetOutputFile(command.Flags())
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
@@ -61,7 +62,7 @@ func fetchHKPrivateKeyF(command *cobra.Command, args []string) error {
 	if outputFile == "" {
 		fmt.Print(string(keyData))
 	} else {
-		outFile, err := os.Create(outputFile)
+		outFile, err := os.OpenFile(outputFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
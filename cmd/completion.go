@@ -15,8 +15,10 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
 
+	"github.com/astarte-platform/astartectl/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -24,6 +26,11 @@ import (
 var completionCmd = &cobra.Command{
 	Use:   "completion",
 	Short: "Generate shell completions",
+	Long: `Generate shell completions.
+
+Besides commands and flags, completions include contexts, clusters and the names of the resources
+in the realm of the current context (or of --context), such as interfaces, triggers, groups and
+devices. These are fetched from Astarte and cached for a minute in the config directory.`,
 }
 
 var completionBashCmd = &cobra.Command{
@@ -78,3 +85,14 @@ func init() {
 
 	rootCmd.AddCommand(completionCmd)
 }
+
+// registerFlagCompletions registers the completion of the values of global flags. It must be called
+// after the flags are defined.
+func registerFlagCompletions() {
+	for _, flag := range []string{"context", "contexts"} {
+		if err := rootCmd.RegisterFlagCompletionFunc(flag, utils.CompleteContexts); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+}
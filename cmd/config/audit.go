@@ -0,0 +1,115 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/astarte-platform/astartectl/config"
+	"github.com/astarte-platform/astartectl/printer"
+	"github.com/jedib0t/go-pretty/table"
+	"github.com/spf13/cobra"
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Query the audit log",
+	Long: `Query the local audit log, where every request changing the state of Astarte sent by
+astartectl is recorded along with its result, the OS user, the context and the command line.
+
+The audit log is audit.log in the config directory, unless another path is set through
+audit-log in the base configuration or --audit-log.
+
+--since and --until accept either an RFC3339 timestamp or a duration before now.`,
+	Example: `  astartectl config audit --since 24h --realm test
+  astartectl config audit --resource interfaces/org.astarte-platform.genericsensors.Values -o json`,
+	Args: cobra.ExactArgs(0),
+	RunE: auditF,
+}
+
+func init() {
+	auditCmd.Flags().String("since", "", "Show only entries recorded at or after this time")
+	auditCmd.Flags().String("until", "", "Show only entries recorded before this time")
+	auditCmd.Flags().String("realm", "", "Show only entries targeting this realm")
+	auditCmd.Flags().String("resource", "", "Show only entries whose resource contains this string")
+
+	ConfigCmd.AddCommand(auditCmd)
+}
+
+func auditF(command *cobra.Command, args []string) error {
+	since, err := auditTimeFlag(command, "since")
+	if err != nil {
+		return err
+	}
+	until, err := auditTimeFlag(command, "until")
+	if err != nil {
+		return err
+	}
+	realm, err := command.Flags().GetString("realm")
+	if err != nil {
+		return err
+	}
+	resource, err := command.Flags().GetString("resource")
+	if err != nil {
+		return err
+	}
+
+	entries, err := config.ReadAuditLog(config.GetAuditLogPath())
+	if err != nil {
+		return err
+	}
+
+	matching := []config.AuditEntry{}
+	for _, e := range entries {
+		switch {
+		case !since.IsZero() && e.Time.Before(since):
+		case !until.IsZero() && !e.Time.Before(until):
+		case realm != "" && e.Realm != realm:
+		case resource != "" && !strings.Contains(e.Resource, resource):
+		default:
+			matching = append(matching, e)
+		}
+	}
+
+	t := printer.NewTable()
+	t.AppendHeader(table.Row{"Time", "User", "Context", "Realm", "Method", "Resource", "Result"})
+	for _, e := range matching {
+		result := e.Error
+		if result == "" {
+			result = strconv.Itoa(e.Status)
+		}
+		t.AppendRow(table.Row{e.Time.Local().Format(time.RFC3339), e.User, e.Context, e.Realm, e.Method, e.Resource, result})
+	}
+	return printer.PrintTable(t, matching)
+}
+
+// auditTimeFlag parses a time flag, either an RFC3339 timestamp or a duration before now.
+// An empty flag returns the zero time.
+func auditTimeFlag(command *cobra.Command, name string) (time.Time, error) {
+	value, err := command.Flags().GetString(name)
+	if err != nil || value == "" {
+		return time.Time{}, err
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --%s %q: must be an RFC3339 timestamp or a duration", name, value)
+}
@@ -90,7 +90,7 @@ var clustersDeleteCmd = &cobra.Command{
 func init() {
 	ConfigCmd.AddCommand(clustersCmd)
 
-	clustersGetHousekeepingKeyCmd.Flags().StringP("output", "o", "", "If specified, housekeeping key will be saved to specified file")
+	utils.AddOutputFileFlag(clustersGetHousekeepingKeyCmd, "If specified, housekeeping key will be saved to specified file")
 
 	clustersCreateCmd.Flags().StringP("housekeeping-key", "k", "", "Path to PEM encoded private key used as housekeeping key")
 	if err := clustersCreateCmd.MarkFlagFilename("housekeeping-key"); err != nil {
@@ -200,7 +200,7 @@ func clustersShowF(command *cobra.Command, args []string) error {
 
 func clustersGetHousekeepingKeyF(command *cobra.Command, args []string) error {
 	clusterName := args[0]
-	output, err := command.Flags().GetString("output")
+	output, err := utils.GetOutputFile(command.Flags())
 	if err != nil {
 		return err
 	}
@@ -215,7 +215,7 @@ func clustersGetHousekeepingKeyF(command *cobra.Command, args []string) error {
 		fmt.Fprintf(os.Stderr, "Cluster %s has no Housekeeping Key associated\n", clusterName)
 	}
 
-	decoded, err := base64.StdEncoding.DecodeString(cluster.Housekeeping.Key)
+	decoded, err := config.DecodeKey(cluster.Housekeeping.Key)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
@@ -223,7 +223,7 @@ func clustersGetHousekeepingKeyF(command *cobra.Command, args []string) error {
 
 	if output != "" {
 		// Save to file
-		if err := os.WriteFile(output, decoded, 0644); err != nil {
+		if err := os.WriteFile(output, decoded, 0600); err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
 		}
@@ -0,0 +1,30 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"github.com/astarte-platform/astartectl/utils"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	for _, cmd := range []*cobra.Command{contextsShowCmd, contextsGetRealmKeyCmd, contextsTokenCmd,
+		contextsUpdateCmd, contextsDeleteCmd, setCurrentContextCmd} {
+		cmd.ValidArgsFunction = utils.CompleteFirstArg(utils.CompleteContexts)
+	}
+	for _, cmd := range []*cobra.Command{clustersShowCmd, clustersGetHousekeepingKeyCmd, clustersUpdateCmd, clustersDeleteCmd} {
+		cmd.ValidArgsFunction = utils.CompleteFirstArg(utils.CompleteClusters)
+	}
+}
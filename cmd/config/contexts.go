@@ -20,6 +20,7 @@ import (
 	"os"
 	"text/tabwriter"
 
+	"github.com/astarte-platform/astarte-go/astarteservices"
 	"github.com/astarte-platform/astartectl/config"
 	"github.com/astarte-platform/astartectl/utils"
 	"github.com/spf13/cobra"
@@ -59,6 +60,19 @@ var contextsGetRealmKeyCmd = &cobra.Command{
 	RunE:    contextsGetRealmKeyF,
 }
 
+var contextsTokenCmd = &cobra.Command{
+	Use:   "token <context_name>",
+	Short: "Print a token for a context",
+	Long: `Print a token granting full access to the given services, using the credentials of a context.
+
+Tokens generated from private keys are cached and reused until they expire, so this command can be
+called repeatedly, e.g. to authenticate curl requests. Housekeeping tokens are generated using the
+credentials of the context's cluster.`,
+	Example: `  curl -H "Authorization: Bearer $(astartectl config contexts token mycontext --service appengine)" ...`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    contextsTokenF,
+}
+
 var contextsCreateCmd = &cobra.Command{
 	Use:     "create <context_name>",
 	Short:   "Create context",
@@ -90,7 +104,14 @@ var contextsDeleteCmd = &cobra.Command{
 func init() {
 	ConfigCmd.AddCommand(contextsCmd)
 
-	contextsGetRealmKeyCmd.Flags().StringP("output", "o", "", "If specified, private key will be saved to specified file")
+	utils.AddOutputFileFlag(contextsGetRealmKeyCmd, "If specified, private key will be saved to specified file")
+
+	realmServiceNames := []string{}
+	for _, s := range utils.RealmServices {
+		realmServiceNames = append(realmServiceNames, s.String())
+	}
+	contextsTokenCmd.Flags().StringSlice("service", realmServiceNames,
+		"The services the token should grant access to. Can be specified multiple times or as a comma separated list.")
 
 	contextsCreateCmd.Flags().StringP("realm-private-key", "k", "", "Path to PEM encoded private key used as realm key")
 	if err := contextsCreateCmd.MarkFlagFilename("realm-private-key"); err != nil {
@@ -105,6 +126,10 @@ func init() {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
+	if err := contextsCreateCmd.RegisterFlagCompletionFunc("cluster", utils.CompleteClusters); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 	contextsCreateCmd.Flags().BoolP("activate", "a", false, "When specified, activates the context upon its creation")
 
 	contextsUpdateCmd.Flags().StringP("realm-private-key", "k", "", "Path to PEM encoded private key used as realm key")
@@ -116,12 +141,17 @@ func init() {
 	// TODO: Define the -t shorthand once we fix all the token everywhere mess
 	contextsUpdateCmd.Flags().String("realm-token", "", "A JWT token used to authenticate against the realm. To be provided if key is not available")
 	contextsUpdateCmd.Flags().StringP("cluster", "c", "", "The cluster name the context should refer to. Must be an existing astartectl cluster")
+	if err := contextsUpdateCmd.RegisterFlagCompletionFunc("cluster", utils.CompleteClusters); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 	contextsUpdateCmd.Flags().BoolP("activate", "a", false, "When specified, activates the context after updating it")
 
 	contextsCmd.AddCommand(
 		contextsListCmd,
 		contextsShowCmd,
 		contextsGetRealmKeyCmd,
+		contextsTokenCmd,
 		contextsCreateCmd,
 		contextsUpdateCmd,
 		contextsDeleteCmd,
@@ -196,7 +226,7 @@ func contextsShowF(command *cobra.Command, args []string) error {
 
 func contextsGetRealmKeyF(command *cobra.Command, args []string) error {
 	contextName := args[0]
-	output, err := command.Flags().GetString("output")
+	output, err := utils.GetOutputFile(command.Flags())
 	if err != nil {
 		return err
 	}
@@ -211,7 +241,7 @@ func contextsGetRealmKeyF(command *cobra.Command, args []string) error {
 		fmt.Fprintf(os.Stderr, "Context %s has no Realm Key associated\n", contextName)
 	}
 
-	decoded, err := base64.StdEncoding.DecodeString(context.Realm.Key)
+	decoded, err := config.DecodeKey(context.Realm.Key)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
@@ -219,7 +249,7 @@ func contextsGetRealmKeyF(command *cobra.Command, args []string) error {
 
 	if output != "" {
 		// Save to file
-		if err := os.WriteFile(output, decoded, 0644); err != nil {
+		if err := os.WriteFile(output, decoded, 0600); err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
 		}
@@ -231,6 +261,29 @@ func contextsGetRealmKeyF(command *cobra.Command, args []string) error {
 	return nil
 }
 
+func contextsTokenF(command *cobra.Command, args []string) error {
+	serviceNames, err := command.Flags().GetStringSlice("service")
+	if err != nil {
+		return err
+	}
+	services := []astarteservices.AstarteService{}
+	for _, name := range serviceNames {
+		service, err := astarteservices.FromString(name)
+		if err != nil {
+			return fmt.Errorf("invalid service %s", name)
+		}
+		services = append(services, service)
+	}
+
+	token, err := utils.ContextToken(args[0], services)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(token)
+	return nil
+}
+
 func contextsCreateF(command *cobra.Command, args []string) error {
 	return performContextCreation(args[0], false, command, args)
 }
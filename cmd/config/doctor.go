@@ -0,0 +1,423 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/astarte-platform/astarte-go/auth"
+	"github.com/astarte-platform/astartectl/config"
+	"github.com/astarte-platform/astartectl/printer"
+	"github.com/astarte-platform/astartectl/utils"
+	"github.com/jedib0t/go-pretty/table"
+	"github.com/spf13/cobra"
+)
+
+const (
+	doctorError   = "error"
+	doctorWarning = "warning"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the local configuration for problems",
+	Long: `Check the whole local configuration, reporting the problems of every cluster and context.
+
+The base configuration must point to an existing context, contexts must refer to existing
+clusters, URLs must be valid, keys must be decodable private keys, tokens must be valid JWTs
+which are not expired, and credential commands must be found. Encrypted keys are checked when
+their passphrase is available.
+
+With --check-reachability, the health endpoint of each API of each cluster is queried too.
+
+The command exits with a non-zero status when any error is found. Warnings are just reported.`,
+	Example: `  astartectl config doctor
+  astartectl config doctor --check-reachability -o json`,
+	Args: cobra.ExactArgs(0),
+	RunE: doctorF,
+}
+
+// doctorProblem is a problem found in a configuration entry
+type doctorProblem struct {
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// doctorReport holds the problems found in a configuration entry
+type doctorReport struct {
+	Kind     string          `json:"kind"`
+	Name     string          `json:"name"`
+	Problems []doctorProblem `json:"problems"`
+}
+
+func (r *doctorReport) errorf(format string, a ...interface{}) {
+	r.Problems = append(r.Problems, doctorProblem{Severity: doctorError, Message: fmt.Sprintf(format, a...)})
+}
+
+func (r *doctorReport) warnf(format string, a ...interface{}) {
+	r.Problems = append(r.Problems, doctorProblem{Severity: doctorWarning, Message: fmt.Sprintf(format, a...)})
+}
+
+func (r *doctorReport) result() string {
+	result := "ok"
+	for _, p := range r.Problems {
+		if p.Severity == doctorError {
+			return doctorError
+		}
+		result = doctorWarning
+	}
+	return result
+}
+
+func init() {
+	doctorCmd.Flags().Bool("check-reachability", false, "Query the health endpoint of the APIs of each cluster")
+	doctorCmd.Flags().Duration("timeout", 10*time.Second, "Timeout of each health check")
+
+	ConfigCmd.AddCommand(doctorCmd)
+}
+
+func doctorF(command *cobra.Command, args []string) error {
+	checkReachability, err := command.Flags().GetBool("check-reachability")
+	if err != nil {
+		return err
+	}
+	timeout, err := command.Flags().GetDuration("timeout")
+	if err != nil {
+		return err
+	}
+
+	configDir := config.GetConfigDir()
+	clusters, err := config.ListClusterConfigurations(configDir)
+	if err != nil {
+		return err
+	}
+	contexts, err := config.ListContextConfigurations(configDir)
+	if err != nil {
+		return err
+	}
+
+	sort.Strings(clusters)
+	sort.Strings(contexts)
+
+	reports := []doctorReport{checkBaseConfiguration(configDir, contexts)}
+	for _, cluster := range clusters {
+		reports = append(reports, checkClusterConfiguration(configDir, cluster, checkReachability, timeout))
+	}
+	for _, context := range contexts {
+		reports = append(reports, checkContextConfiguration(configDir, context, clusters))
+	}
+
+	t := printer.NewTable()
+	t.AppendHeader(table.Row{"Kind", "Name", "Result", "Problems"})
+	failed := false
+	for _, r := range reports {
+		problems := []string{}
+		for _, p := range r.Problems {
+			problems = append(problems, fmt.Sprintf("%s: %s", p.Severity, p.Message))
+		}
+		t.AppendRow(table.Row{r.Kind, r.Name, r.result(), strings.Join(problems, "\n")})
+		failed = failed || r.result() == doctorError
+	}
+	if err := printer.PrintTable(t, reports); err != nil {
+		return err
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+	return nil
+}
+
+func checkBaseConfiguration(configDir string, contexts []string) doctorReport {
+	report := doctorReport{Kind: "base", Name: configDir, Problems: []doctorProblem{}}
+	baseConfig, err := config.LoadBaseConfiguration(configDir)
+	if err != nil {
+		report.errorf("could not load the base configuration: %s", err)
+		return report
+	}
+
+	switch {
+	case baseConfig.CurrentContext == "":
+		report.warnf("no current context set")
+	case !contains(contexts, baseConfig.CurrentContext):
+		report.errorf("the current context %q does not exist", baseConfig.CurrentContext)
+	}
+
+	if baseConfig.KeyCheck != "" {
+		if passphrase, err := config.KeyPassphrase(false); err != nil {
+			report.warnf("could not check the passphrase of encrypted keys: %s", err)
+		} else if _, err := config.DecryptKey(baseConfig.KeyCheck, passphrase); err != nil {
+			report.errorf("the passphrase of encrypted keys is wrong")
+		}
+	}
+	return report
+}
+
+func checkClusterConfiguration(configDir, clusterName string, checkReachability bool, timeout time.Duration) doctorReport {
+	report := doctorReport{Kind: "cluster", Name: clusterName, Problems: []doctorProblem{}}
+	cluster, err := config.LoadClusterConfiguration(configDir, clusterName)
+	if err != nil {
+		report.errorf("could not load the cluster: %s", err)
+		return report
+	}
+
+	serviceURLs := checkClusterURLs(&report, cluster)
+	checkPrivateKey(&report, "housekeeping key", cluster.Housekeeping.Key)
+	checkToken(&report, "housekeeping token", cluster.Housekeeping.Token, []string{"a_ha"})
+	checkCredentialCommand(&report, "housekeeping token-command", cluster.Housekeeping.TokenCommand)
+	checkCredentialCommand(&report, "housekeeping key-command", cluster.Housekeeping.KeyCommand)
+
+	if cluster.Retry.MaxAttempts < 0 {
+		report.errorf("retry max-attempts can't be negative")
+	}
+	if _, err := time.ParseDuration(cluster.Retry.Backoff); cluster.Retry.Backoff != "" && err != nil {
+		report.errorf("invalid retry backoff: %s", err)
+	}
+	if _, err := time.ParseDuration(cluster.Retry.MaxBackoff); cluster.Retry.MaxBackoff != "" && err != nil {
+		report.errorf("invalid retry max-backoff: %s", err)
+	}
+
+	tlsConfig, err := utils.NewTLSConfig(cluster.TLS, false)
+	if err != nil {
+		report.errorf("invalid tls configuration: %s", err)
+		return report
+	}
+	if checkReachability {
+		httpClient := &http.Client{
+			Timeout:   timeout,
+			Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: tlsConfig},
+		}
+		for _, service := range doctorServices {
+			if serviceURL, ok := serviceURLs[service]; ok {
+				checkHealth(&report, httpClient, service, serviceURL)
+			}
+		}
+	}
+	return report
+}
+
+// doctorServices are the APIs of a cluster, named after their path under the base URL
+var doctorServices = []string{"housekeeping", "realmmanagement", "pairing", "appengine"}
+
+// checkClusterURLs checks the URLs of cluster, returning the valid URLs of its APIs
+func checkClusterURLs(report *doctorReport, cluster config.ClusterFile) map[string]*url.URL {
+	individualURLs := map[string]string{
+		"housekeeping":    cluster.IndividualURLs.Housekeeping,
+		"realmmanagement": cluster.IndividualURLs.RealmManagement,
+		"pairing":         cluster.IndividualURLs.Pairing,
+		"appengine":       cluster.IndividualURLs.AppEngine,
+	}
+	serviceURLs := map[string]*url.URL{}
+
+	hasIndividualURLs := false
+	for _, service := range doctorServices {
+		if individualURLs[service] == "" {
+			continue
+		}
+		hasIndividualURLs = true
+		if u, err := parseAPIURL(individualURLs[service]); err != nil {
+			report.errorf("invalid %s URL: %s", service, err)
+		} else {
+			serviceURLs[service] = u
+		}
+	}
+	if cluster.IndividualURLs.Flow != "" {
+		if _, err := parseAPIURL(cluster.IndividualURLs.Flow); err != nil {
+			report.errorf("invalid flow URL: %s", err)
+		}
+	}
+
+	switch {
+	case hasIndividualURLs:
+		// Individual URLs take precedence over the base URL
+		for _, service := range doctorServices {
+			if individualURLs[service] == "" {
+				report.warnf("no %s URL, the %s API can't be used", service, service)
+			}
+		}
+	case cluster.URL == "":
+		report.errorf("neither url nor individual URLs are set")
+	default:
+		baseURL, err := parseAPIURL(cluster.URL)
+		if err != nil {
+			report.errorf("invalid url: %s", err)
+			break
+		}
+		for _, service := range doctorServices {
+			serviceURLs[service] = baseURL.JoinPath(service)
+		}
+	}
+	return serviceURLs
+}
+
+func parseAPIURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("%q must be an http or https URL", rawURL)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("%q has no host", rawURL)
+	}
+	return u, nil
+}
+
+func checkHealth(report *doctorReport, httpClient *http.Client, service string, serviceURL *url.URL) {
+	res, err := httpClient.Get(serviceURL.JoinPath("health").String())
+	if err != nil {
+		report.errorf("%s is not reachable: %s", service, err)
+		return
+	}
+	res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		report.errorf("%s is not healthy: %s", service, res.Status)
+	}
+}
+
+func checkContextConfiguration(configDir, contextName string, clusters []string) doctorReport {
+	report := doctorReport{Kind: "context", Name: contextName, Problems: []doctorProblem{}}
+	context, err := config.LoadContextConfiguration(configDir, contextName)
+	if err != nil {
+		report.errorf("could not load the context: %s", err)
+		return report
+	}
+
+	switch {
+	case context.Cluster == "":
+		report.errorf("no cluster set")
+	case !contains(clusters, context.Cluster):
+		report.errorf("the cluster %q does not exist", context.Cluster)
+	}
+
+	realm := context.Realm
+	hasCredentials := realm.Key != "" || realm.Token != "" || realm.TokenCommand != nil || realm.KeyCommand != nil
+	if realm.Name == "" {
+		if hasCredentials {
+			report.errorf("realm credentials are set, but the realm name is not")
+		}
+		// The context refers to housekeeping only
+		return report
+	}
+	if !hasCredentials {
+		report.warnf("no realm credentials set, a token or a key has to be passed to every command")
+	}
+
+	checkPrivateKey(&report, "realm key", realm.Key)
+	checkToken(&report, "realm token", realm.Token, []string{"a_rma", "a_aea", "a_pa", "a_ch", "a_f"})
+	checkCredentialCommand(&report, "realm token-command", realm.TokenCommand)
+	checkCredentialCommand(&report, "realm key-command", realm.KeyCommand)
+	return report
+}
+
+// checkPrivateKey checks that key, a key field of the configuration, holds a PEM private key
+func checkPrivateKey(report *doctorReport, name, key string) {
+	if key == "" {
+		return
+	}
+	if config.IsEncryptedKey(key) {
+		if _, err := config.KeyPassphrase(false); err != nil {
+			report.warnf("could not check the encrypted %s: %s", name, err)
+			return
+		}
+	}
+
+	decoded, err := config.DecodeKey(key)
+	if err != nil {
+		report.errorf("could not decode the %s: %s", name, err)
+		return
+	}
+	if _, err := auth.ParsePrivateKeyFromPEM(decoded); err != nil {
+		report.errorf("the %s is not a valid private key: %s", name, err)
+	}
+}
+
+// checkToken checks that token is a JWT which is not expired, and has at least one of authorizationClaims.
+// The signature can't be verified, as that requires the public key known to Astarte.
+func checkToken(report *doctorReport, name, token string, authorizationClaims []string) {
+	if token == "" {
+		return
+	}
+	claims, err := decodeTokenClaims(token)
+	if err != nil {
+		report.errorf("the %s is not a valid JWT: %s", name, err)
+		return
+	}
+
+	if exp, ok := claims["exp"].(float64); ok {
+		expiration := time.Unix(int64(exp), 0)
+		if time.Now().After(expiration) {
+			report.errorf("the %s expired at %s", name, expiration.Local().Format(time.RFC3339))
+		}
+	}
+
+	for _, claim := range authorizationClaims {
+		if _, ok := claims[claim]; ok {
+			return
+		}
+	}
+	report.errorf("the %s has none of the authorization claims %s", name, strings.Join(authorizationClaims, ", "))
+}
+
+// decodeTokenClaims returns the claims of a JWT, without verifying it
+func decodeTokenClaims(token string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("a JWT must have 3 parts")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, err
+	}
+	claims := map[string]interface{}{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// checkCredentialCommand checks that the executable of command can be found. The command isn't run.
+func checkCredentialCommand(report *doctorReport, name string, command *config.ExecCredentialConfiguration) {
+	if command == nil {
+		return
+	}
+	if command.Command == "" {
+		report.errorf("no command set in %s", name)
+		return
+	}
+	if _, err := exec.LookPath(command.Command); err != nil {
+		report.errorf("%s: %s", name, err)
+	}
+}
+
+func contains(list []string, item string) bool {
+	for _, i := range list {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
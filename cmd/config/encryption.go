@@ -0,0 +1,86 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/astarte-platform/astartectl/config"
+	"github.com/spf13/cobra"
+)
+
+var encryptCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "Encrypt the private keys stored in the configuration",
+	Long: `Encrypt all realm and housekeeping private keys stored in the configuration directory
+with a passphrase. Keys added afterwards are encrypted as well.
+
+The passphrase is read from the ASTARTECTL_KEY_PASSPHRASE environment variable or prompted for,
+and it will be required every time a key is used.`,
+	Example: `  astartectl config encrypt`,
+	Args:    cobra.ExactArgs(0),
+	RunE:    encryptF,
+}
+
+var decryptCmd = &cobra.Command{
+	Use:   "decrypt",
+	Short: "Decrypt the private keys stored in the configuration",
+	Long: `Decrypt all realm and housekeeping private keys stored in the configuration directory,
+storing them in clear from now on.`,
+	Example: `  astartectl config decrypt`,
+	Args:    cobra.ExactArgs(0),
+	RunE:    decryptF,
+}
+
+func init() {
+	ConfigCmd.AddCommand(encryptCmd)
+	ConfigCmd.AddCommand(decryptCmd)
+}
+
+func encryptF(command *cobra.Command, args []string) error {
+	configDir := config.GetConfigDir()
+	if config.KeysEncrypted(configDir) {
+		return fmt.Errorf("keys in %s are already encrypted", configDir)
+	}
+
+	passphrase, err := config.KeyPassphrase(true)
+	if err != nil {
+		return err
+	}
+	if err := config.EncryptConfigDirectory(configDir, passphrase); err != nil {
+		return err
+	}
+
+	fmt.Printf("Keys in %s are now encrypted\n", configDir)
+	return nil
+}
+
+func decryptF(command *cobra.Command, args []string) error {
+	configDir := config.GetConfigDir()
+	if !config.KeysEncrypted(configDir) {
+		return fmt.Errorf("keys in %s are not encrypted", configDir)
+	}
+
+	passphrase, err := config.KeyPassphrase(false)
+	if err != nil {
+		return err
+	}
+	if err := config.DecryptConfigDirectory(configDir, passphrase); err != nil {
+		return err
+	}
+
+	fmt.Printf("Keys in %s are now stored in clear\n", configDir)
+	return nil
+}
@@ -16,10 +16,16 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 
+	"filippo.io/age"
 	"github.com/astarte-platform/astartectl/config"
+	"github.com/astarte-platform/astartectl/printer"
+	"github.com/astarte-platform/astartectl/utils"
+	"github.com/jedib0t/go-pretty/table"
 	"github.com/spf13/cobra"
 )
 
@@ -27,36 +33,59 @@ var configImportCmd = &cobra.Command{
 	Use:   "import <config_file>",
 	Short: "Import configuration file",
 	Long: `Import a configuration file previously exported with astartectl. This might
-	be a partial export or a full export, and it should be in JSON format. By default, existing
-	clusters and context won't be overwritten - you can force this behavior with --overwrite.`,
-	Example: `  astartectl config import export.json`,
-	Args:    cobra.ExactArgs(1),
-	RunE:    configImportF,
+	be a partial export or a full export, and it should be in JSON format.
+
+	Clusters and contexts conflicting with existing ones are imported according to --strategy:
+	keep-local (the default) leaves existing ones untouched, prefer-import overwrites them, and
+	rename-conflicts imports them with an -imported suffix. The base configuration is imported only
+	when missing, otherwise just the current context is set, if it is not already.
+
+	--plan shows what would be added, changed or left untouched, without importing anything. The
+	import is transactional: either the whole configuration file is imported, or nothing is.
+
+	Encrypted exports are decrypted with the age identities given through --identity or, when none
+	is given, with a passphrase read from ASTARTECTL_BUNDLE_PASSPHRASE or prompted for.`,
+	Example: `  astartectl config import export.json
+  astartectl config import export.json --plan --strategy rename-conflicts
+  astartectl config import export.age --identity ~/.config/age/keys.txt`,
+	Args: cobra.ExactArgs(1),
+	RunE: configImportF,
 }
 
 var configExportCmd = &cobra.Command{
-	Use:   "export [-o <output_file>]",
+	Use:   "export [--output-file <output_file>]",
 	Short: "Export current astartectl configuration",
 	Long: `Export current astartectl configuration to a JSON file, which can be later imported through
 	astartectl config import. By default, the complete list of clusters and contexts is exported, but you can
 	tweak this behavior by using --clusters and --contexts, providing a list of names. By default, the resulting
-	JSON file is printed on stdout, but it can also be saved to a file by specifying -o.`,
-	Example: `  astartectl housekeeping realms create myrealm --realm-public-key /path/to/public_key`,
-	Args:    cobra.ExactArgs(0),
-	RunE:    configExportF,
+	JSON file is printed on stdout, but it can also be saved to a file by specifying -o.
+
+	Exports include private keys and tokens. To share them, either strip keys and tokens with
+	--redact-secrets, or encrypt the export with --encrypt, using a passphrase read from
+	ASTARTECTL_BUNDLE_PASSPHRASE or prompted for, or with --recipient, for age recipients.`,
+	Example: `  astartectl config export -o export.json --redact-secrets
+  astartectl config export -o export.age --recipient age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p`,
+	Args: cobra.ExactArgs(0),
+	RunE: configExportF,
 }
 
 func init() {
 	ConfigCmd.AddCommand(configImportCmd)
 	ConfigCmd.AddCommand(configExportCmd)
 
-	configImportCmd.Flags().Bool("overwrite", false, "When specified, overwrites existing clusters or contexts with matching filenames")
+	configImportCmd.Flags().Bool("overwrite", false, "When specified, overwrites existing clusters or contexts with matching filenames. Same as --strategy prefer-import")
+	configImportCmd.Flags().String("strategy", string(config.KeepLocalStrategy), fmt.Sprintf("How clusters and contexts conflicting with existing ones are imported. One of: %s", strings.Join(config.ImportStrategies, ", ")))
+	configImportCmd.Flags().Bool("plan", false, "When specified, shows how the configuration would be imported without importing it")
 	configImportCmd.Flags().StringSlice("clusters", []string{}, "A list of clusters to be imported, comma separated. If not specified, all clusters will be imported")
 	configImportCmd.Flags().StringSlice("contexts", []string{}, "A list of contexts to be imported, comma separated. If not specified, all contexts will be imported")
+	configImportCmd.Flags().StringSlice("identity", []string{}, "Path to a file of age identities decrypting the configuration file. Can be repeated")
 
-	configExportCmd.Flags().StringP("output", "o", "", "If specified, configuration will be exported to specified file")
+	utils.AddOutputFileFlag(configExportCmd, "If specified, configuration will be exported to specified file")
 	configExportCmd.Flags().StringSlice("clusters", []string{}, "A list of clusters to be exported, comma separated. If not specified, all clusters will be exported")
 	configExportCmd.Flags().StringSlice("contexts", []string{}, "A list of contexts to be exported, comma separated. If not specified, all contexts will be exported")
+	configExportCmd.Flags().Bool("redact-secrets", false, "When specified, private keys and tokens are not exported")
+	configExportCmd.Flags().Bool("encrypt", false, "When specified, the export is encrypted with a passphrase")
+	configExportCmd.Flags().StringSlice("recipient", []string{}, "An age recipient, or a file of age recipients, the export is encrypted for. Can be repeated")
 }
 
 func configImportF(command *cobra.Command, args []string) error {
@@ -64,6 +93,24 @@ func configImportF(command *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	strategyFlag, err := command.Flags().GetString("strategy")
+	if err != nil {
+		return err
+	}
+	strategy, err := config.ParseImportStrategy(strategyFlag)
+	if err != nil {
+		return err
+	}
+	if overwrite {
+		if command.Flags().Changed("strategy") && strategy != config.PreferImportStrategy {
+			return errors.New("--overwrite can't be used together with a --strategy other than prefer-import")
+		}
+		strategy = config.PreferImportStrategy
+	}
+	plan, err := command.Flags().GetBool("plan")
+	if err != nil {
+		return err
+	}
 	clusters, err := command.Flags().GetStringSlice("clusters")
 	if err != nil {
 		return err
@@ -72,6 +119,14 @@ func configImportF(command *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	identityFiles, err := command.Flags().GetStringSlice("identity")
+	if err != nil {
+		return err
+	}
+	identities, err := config.ParseAgeIdentities(identityFiles)
+	if err != nil {
+		return err
+	}
 
 	// First of all, read the file content
 	contents, err := os.ReadFile(args[0])
@@ -80,15 +135,24 @@ func configImportF(command *cobra.Command, args []string) error {
 		os.Exit(1)
 	}
 
-	// Load the bundle
-	var bundle config.Bundle
-	if err := json.Unmarshal(contents, &bundle); err != nil {
+	// Load the bundle, decrypting it if needed
+	bundle, err := config.ParseBundle(contents, identities...)
+	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 
+	importPlan, err := config.PlanBundleImport(bundle, config.GetConfigDir(), clusters, contexts, strategy)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if plan {
+		return printImportPlan(importPlan)
+	}
+
 	// Go for it
-	if err := config.LoadBundleToDirectory(bundle, config.GetConfigDir(), clusters, contexts, overwrite); err != nil {
+	if err := config.ApplyImportPlan(importPlan, config.GetConfigDir()); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
@@ -97,8 +161,25 @@ func configImportF(command *cobra.Command, args []string) error {
 	return nil
 }
 
+func printImportPlan(plan config.ImportPlan) error {
+	t := printer.NewTable()
+	t.AppendHeader(table.Row{"Kind", "Name", "Action", "Changes"})
+	for _, e := range plan.Entries {
+		name := e.Name
+		if e.TargetName != e.Name {
+			name = fmt.Sprintf("%s -> %s", e.Name, e.TargetName)
+		}
+		changes := []string{}
+		for _, c := range e.Changes {
+			changes = append(changes, fmt.Sprintf("%s: %q -> %q", c.Field, c.Local, c.Imported))
+		}
+		t.AppendRow(table.Row{e.Kind, name, e.Action, strings.Join(changes, "\n")})
+	}
+	return printer.PrintTable(t, plan)
+}
+
 func configExportF(command *cobra.Command, args []string) error {
-	output, err := command.Flags().GetString("output")
+	output, err := utils.GetOutputFile(command.Flags())
 	if err != nil {
 		return err
 	}
@@ -110,6 +191,36 @@ func configExportF(command *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	redactSecrets, err := command.Flags().GetBool("redact-secrets")
+	if err != nil {
+		return err
+	}
+	encrypt, err := command.Flags().GetBool("encrypt")
+	if err != nil {
+		return err
+	}
+	recipientFlags, err := command.Flags().GetStringSlice("recipient")
+	if err != nil {
+		return err
+	}
+	if encrypt && len(recipientFlags) > 0 {
+		return errors.New("--encrypt and --recipient can't be used together")
+	}
+	recipients, err := config.ParseAgeRecipients(recipientFlags)
+	if err != nil {
+		return err
+	}
+	if encrypt {
+		passphrase, err := config.BundlePassphrase(true)
+		if err != nil {
+			return err
+		}
+		recipient, err := age.NewScryptRecipient(passphrase)
+		if err != nil {
+			return err
+		}
+		recipients = append(recipients, recipient)
+	}
 
 	// Go for it
 	bundle, err := config.CreateBundleFromDirectory(config.GetConfigDir(), clusters, contexts)
@@ -118,8 +229,17 @@ func configExportF(command *cobra.Command, args []string) error {
 		os.Exit(1)
 	}
 
-	// Get some JSON out of it
-	jsonBytes, err := json.Marshal(bundle)
+	if redactSecrets {
+		bundle = config.RedactBundleSecrets(bundle)
+	}
+
+	// Get some JSON out of it, encrypted if requested
+	var jsonBytes []byte
+	if len(recipients) > 0 {
+		jsonBytes, err = config.EncryptBundle(bundle, recipients...)
+	} else {
+		jsonBytes, err = json.Marshal(bundle)
+	}
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
@@ -128,7 +248,8 @@ func configExportF(command *cobra.Command, args []string) error {
 	if output == "" {
 		fmt.Println(string(jsonBytes))
 	} else {
-		if err := os.WriteFile(output, jsonBytes, 0644); err != nil {
+		// Unless redacted, exports contain private keys and tokens
+		if err := os.WriteFile(output, jsonBytes, 0600); err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
 		}
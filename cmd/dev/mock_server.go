@@ -0,0 +1,191 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dev
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/astarte-platform/astarte-go/auth"
+	"github.com/astarte-platform/astartectl/mockserver"
+	"github.com/spf13/cobra"
+)
+
+var mockServerCmd = &cobra.Command{
+	Use:   "mock-server",
+	Short: "Run an in-memory mock of the Astarte APIs",
+	Long: `Run an in-memory mock of the Housekeeping, Realm Management, Pairing and AppEngine APIs,
+covering the endpoints used by astartectl: realms, interfaces, triggers, trigger delivery
+policies, devices, groups, aliases, attributes and samples.
+
+Devices can't connect to the mock, so their introspection must be given in the seed file:
+publishing data on a server owned interface also adds it to the device introspection.
+
+Requests are authenticated like Astarte does: tokens are validated against the housekeeping
+public key for Housekeeping, and against the realm public key for all other APIs, including
+their authorization claims. Nothing is persisted, the state is lost when the server stops.
+
+The housekeeping key is read from --housekeeping-key, and generated there if the file does
+not exist. When it is not given, a new key is generated in a temporary directory.
+
+The initial state can be loaded from a YAML or JSON seed file such as:
+
+  realms:
+    - realm_name: test
+      jwt_public_key_pem: |
+        -----BEGIN PUBLIC KEY-----
+        ...
+      interfaces: [...]
+      devices:
+        - id: 2TBn-jNESuuHamE2Zo1anA
+          aliases: {name: my-device}
+          introspection:
+            org.example.Sensors: {major: 0, minor: 1}
+          samples:
+            org.example.Sensors:
+              /temperature:
+                - {value: 21.5, timestamp: "2026-01-01T00:00:00Z"}`,
+	Example: `  astartectl dev mock-server --housekeeping-key housekeeping.pem
+  astartectl config clusters create mock --api-url http://localhost:4000 --housekeeping-key housekeeping.pem`,
+	Args: cobra.NoArgs,
+	RunE: mockServerF,
+}
+
+func init() {
+	mockServerCmd.Flags().String("listen", "127.0.0.1:4000", "Address the mock server listens on")
+	mockServerCmd.Flags().String("housekeeping-key", "", "Path to the PEM encoded housekeeping private key. Generated if it does not exist")
+	_ = mockServerCmd.MarkFlagFilename("housekeeping-key")
+	mockServerCmd.Flags().String("seed", "", "Path to a YAML or JSON file holding the initial state of the mock server")
+	_ = mockServerCmd.MarkFlagFilename("seed", "yaml", "yml", "json")
+
+	DevCmd.AddCommand(mockServerCmd)
+}
+
+func mockServerF(command *cobra.Command, args []string) error {
+	listen, err := command.Flags().GetString("listen")
+	if err != nil {
+		return err
+	}
+	housekeepingKey, err := command.Flags().GetString("housekeeping-key")
+	if err != nil {
+		return err
+	}
+	seed, err := command.Flags().GetString("seed")
+	if err != nil {
+		return err
+	}
+
+	if housekeepingKey == "" {
+		dir, err := os.MkdirTemp("", "astartectl-mock-server-")
+		if err != nil {
+			return err
+		}
+		housekeepingKey = filepath.Join(dir, "housekeeping_private.pem")
+	}
+	publicKey, err := loadOrGenerateHousekeepingKey(housekeepingKey)
+	if err != nil {
+		return err
+	}
+
+	state := &mockserver.State{}
+	if seed != "" {
+		if state, err = mockserver.LoadState(seed); err != nil {
+			return fmt.Errorf("could not load seed: %w", err)
+		}
+	}
+	handler, err := mockserver.New(state, publicKey)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", listen)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+
+	fmt.Fprintf(os.Stderr, "Mock Astarte APIs listening on http://%s\n", listener.Addr())
+	fmt.Fprintf(os.Stderr, "Housekeeping private key: %s\n", housekeepingKey)
+	fmt.Fprintln(os.Stderr, "Press Ctrl+C to stop.")
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// loadOrGenerateHousekeepingKey returns the PEM public key matching the private key in fileName,
+// generating a new private key if the file does not exist
+func loadOrGenerateHousekeepingKey(fileName string) ([]byte, error) {
+	var privateKey interface{}
+	privateKeyPEM, err := os.ReadFile(fileName)
+	switch {
+	case err == nil:
+		if privateKey, err = auth.ParsePrivateKeyFromPEM(privateKeyPEM); err != nil {
+			return nil, err
+		}
+	case errors.Is(err, os.ErrNotExist):
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		marshaled, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+		privateKeyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: marshaled})
+		if err := os.WriteFile(fileName, privateKeyPEM, 0600); err != nil {
+			return nil, err
+		}
+		privateKey = key
+	default:
+		return nil, err
+	}
+
+	var pkixBytes []byte
+	switch k := privateKey.(type) {
+	case *ecdsa.PrivateKey:
+		pkixBytes, err = x509.MarshalPKIXPublicKey(k.Public())
+	case *rsa.PrivateKey:
+		pkixBytes, err = x509.MarshalPKIXPublicKey(k.Public())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pkixBytes}), nil
+}
@@ -0,0 +1,339 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/astarte-platform/astartectl/config"
+	"github.com/astarte-platform/astartectl/printer"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/yaml"
+)
+
+// fanOutFlags are the root flags controlling the fan-out, and whether they take a value.
+// They are not passed on to the invocations for each context.
+var fanOutFlags = map[string]bool{
+	"contexts":     true,
+	"all-contexts": false,
+	"parallelism":  true,
+}
+
+// fanOutResult is the outcome of running the command for a single context
+type fanOutResult struct {
+	context string
+	err     error
+	// stdout is the output of the command, when it is buffered rather than prefixed
+	stdout *bytes.Buffer
+}
+
+// maybeFanOut runs the command once for each context selected with --contexts or --all-contexts,
+// invoking astartectl again with --context. It returns false when no fan-out was requested, and
+// never returns otherwise.
+func maybeFanOut(args []string) bool {
+	// Parse global flags only, wherever they are
+	flags := pflag.NewFlagSet("astartectl", pflag.ContinueOnError)
+	flags.AddFlagSet(rootCmd.PersistentFlags())
+	flags.ParseErrorsWhitelist.UnknownFlags = true
+	flags.Usage = func() {}
+	if err := flags.Parse(args); err != nil {
+		return false
+	}
+	patterns, _ := flags.GetStringSlice("contexts")
+	allContexts, _ := flags.GetBool("all-contexts")
+	if len(patterns) == 0 && !allContexts {
+		return false
+	}
+
+	parallelism, _ := flags.GetInt("parallelism")
+	if err := checkFanOutFlags(flags, patterns, allContexts, parallelism); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if allContexts {
+		patterns = []string{"*"}
+	}
+	contexts, err := matchContexts(config.GetConfigDir(), patterns)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	// Prefixing lines would break formats meant to be parsed, buffer them and print them once done
+	outputFormat, _, err := printer.ParseFormat(flags.Lookup("output").Value.String())
+	buffered := err == nil && outputFormat != printer.TableFormat
+
+	commandArgs := stripFanOutFlags(args)
+	results := make([]fanOutResult, len(contexts))
+	output := &prefixedOutput{}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < parallelism && w < len(contexts); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				result := fanOutResult{context: contexts[i]}
+				if buffered {
+					result.stdout = &bytes.Buffer{}
+				}
+				result.err = runForContext(executable, contexts[i], commandArgs, output, result.stdout)
+				results[i] = result
+			}
+		}()
+	}
+	for i := range contexts {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	if buffered {
+		if err := printBufferedResults(outputFormat, results); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	failed := 0
+	for _, r := range results {
+		if r.err != nil {
+			failed++
+		}
+	}
+	fmt.Fprintf(os.Stderr, "\nSucceeded in %d out of %d contexts\n", len(results)-failed, len(results))
+	if failed == 0 {
+		os.Exit(0)
+	}
+	fmt.Fprintln(os.Stderr, "Failed contexts:")
+	for _, r := range results {
+		if r.err != nil {
+			fmt.Fprintf(os.Stderr, "  %s: %s\n", r.context, r.err)
+		}
+	}
+	os.Exit(1)
+	return true
+}
+
+func checkFanOutFlags(flags *pflag.FlagSet, patterns []string, allContexts bool, parallelism int) error {
+	switch {
+	case len(patterns) > 0 && allContexts:
+		return errors.New("--contexts and --all-contexts can't be used together")
+	case flags.Changed("context"):
+		return errors.New("--context can't be used together with --contexts or --all-contexts")
+	case parallelism < 1:
+		return errors.New("--parallelism must be at least 1")
+	}
+	return nil
+}
+
+// matchContexts returns the names of the contexts in configDir matching any of patterns, sorted.
+// Each pattern must match at least one context.
+func matchContexts(configDir string, patterns []string) ([]string, error) {
+	available, err := config.ListContextConfigurations(configDir)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := map[string]bool{}
+	for _, pattern := range patterns {
+		found := false
+		for _, name := range available {
+			ok, err := path.Match(pattern, name)
+			if err != nil {
+				return nil, fmt.Errorf("invalid context pattern %q: %w", pattern, err)
+			}
+			if ok {
+				matched[name] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no context matches %q", pattern)
+		}
+	}
+
+	contexts := []string{}
+	for name := range matched {
+		contexts = append(contexts, name)
+	}
+	sort.Strings(contexts)
+	return contexts, nil
+}
+
+// stripFanOutFlags returns args without the flags controlling the fan-out
+func stripFanOutFlags(args []string) []string {
+	stripped := []string{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return append(stripped, args[i:]...)
+		}
+		name := strings.TrimPrefix(arg, "--")
+		if name == arg {
+			stripped = append(stripped, arg)
+			continue
+		}
+		if j := strings.Index(name, "="); j >= 0 {
+			if _, ok := fanOutFlags[name[:j]]; ok {
+				continue
+			}
+		} else if takesValue, ok := fanOutFlags[name]; ok {
+			if takesValue {
+				i++
+			}
+			continue
+		}
+		stripped = append(stripped, arg)
+	}
+	return stripped
+}
+
+// runForContext runs astartectl with args for contextName, prefixing its output with the context name.
+// When stdout is not nil, the standard output is written there as is instead.
+func runForContext(executable, contextName string, args []string, output *prefixedOutput, stdout *bytes.Buffer) error {
+	command := exec.Command(executable, append([]string{"--context", contextName}, args...)...)
+	stdoutPipe, err := command.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	stderr, err := command.StderrPipe()
+	if err != nil {
+		return err
+	}
+	// There's no way to answer prompts of several commands at once
+	command.Stdin = nil
+
+	if err := command.Start(); err != nil {
+		return err
+	}
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		if stdout != nil {
+			_, _ = io.Copy(stdout, stdoutPipe)
+		} else {
+			output.copyLines(os.Stdout, contextName, stdoutPipe)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		output.copyLines(os.Stderr, contextName, stderr)
+	}()
+	// All output must be read before waiting for the command
+	wg.Wait()
+
+	if err := command.Wait(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return fmt.Errorf("exited with status %d", exitErr.ExitCode())
+		}
+		return err
+	}
+	return nil
+}
+
+// printBufferedResults prints the output of each context. JSON and YAML outputs are combined into
+// a single document keyed by context name, with null for contexts which printed nothing. Other
+// formats are printed one context after the other, each introduced by its name on stderr.
+func printBufferedResults(format printer.Format, results []fanOutResult) error {
+	switch format {
+	case printer.JSONFormat, printer.YAMLFormat:
+		combined := map[string]interface{}{}
+		for _, r := range results {
+			combined[r.context] = parseDocument(r.stdout.Bytes())
+		}
+		var marshaled []byte
+		var err error
+		if format == printer.JSONFormat {
+			if marshaled, err = json.MarshalIndent(combined, "", "    "); err == nil {
+				marshaled = append(marshaled, '\n')
+			}
+		} else {
+			marshaled, err = yaml.Marshal(combined)
+		}
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(marshaled)
+		return err
+	}
+
+	for _, r := range results {
+		if r.stdout.Len() == 0 {
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "[%s]\n", r.context)
+		if _, err := os.Stdout.Write(r.stdout.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseDocument returns the JSON or YAML document printed by a command, or the output itself if
+// it is not a document
+func parseDocument(output []byte) interface{} {
+	trimmed := bytes.TrimSpace(output)
+	if len(trimmed) == 0 {
+		return nil
+	}
+	if json.Valid(trimmed) {
+		// Keep numbers as they were printed
+		return json.RawMessage(trimmed)
+	}
+	var document interface{}
+	if err := yaml.Unmarshal(trimmed, &document); err == nil {
+		return document
+	}
+	return string(output)
+}
+
+// prefixedOutput writes lines coming from several commands, without mixing them
+type prefixedOutput struct {
+	mu sync.Mutex
+}
+
+func (p *prefixedOutput) copyLines(out io.Writer, prefix string, in io.Reader) {
+	reader := bufio.NewReader(in)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			p.mu.Lock()
+			fmt.Fprintf(out, "[%s] %s\n", prefix, strings.TrimSuffix(line, "\n"))
+			p.mu.Unlock()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package housekeeping
+
+import (
+	"github.com/astarte-platform/astartectl/utils"
+)
+
+func init() {
+	realmsShowCmd.ValidArgsFunction = utils.CompleteArgs(completeRealmNames)
+	realmsUpdateCmd.ValidArgsFunction = utils.CompleteArgs(completeRealmNames)
+	realmsDeleteCmd.ValidArgsFunction = utils.CompleteArgs(completeRealmNames)
+	realmsRotateKeyCmd.ValidArgsFunction = utils.CompleteArgs(completeRealmNames)
+}
+
+func completeRealmNames(args []string) ([]string, error) {
+	return utils.RequestCandidates(astarteAPIClient)(astarteAPIClient.ListRealms())
+}
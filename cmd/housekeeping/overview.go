@@ -0,0 +1,259 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package housekeeping
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/astarte-platform/astarte-go/astarteservices"
+	"github.com/astarte-platform/astarte-go/client"
+	"github.com/astarte-platform/astartectl/config"
+	"github.com/astarte-platform/astartectl/printer"
+	"github.com/astarte-platform/astartectl/utils"
+	"github.com/jedib0t/go-pretty/table"
+	"github.com/spf13/cobra"
+)
+
+var realmsOverviewCmd = &cobra.Command{
+	Use:   "overview",
+	Short: "Show an overview of all realms",
+	Long: `Show an overview of all realms in your Astarte instance: their replication and limits and, when a
+context of the current cluster has credentials for the realm, its device stats and the number of its
+interfaces, triggers and trigger delivery policies. Realms are inspected concurrently.`,
+	Example: `  astartectl housekeeping realms overview
+  astartectl housekeeping realms overview -o json`,
+	Args: cobra.NoArgs,
+	RunE: realmsOverviewF,
+}
+
+// realmOverview is the overview of a single realm. Stats and counts are missing when no context
+// has credentials for the realm
+type realmOverview struct {
+	realmDetails
+	Context                 string   `json:"context,omitempty"`
+	TotalDevices            *int64   `json:"total_devices,omitempty"`
+	ConnectedDevices        *int64   `json:"connected_devices,omitempty"`
+	Interfaces              *int     `json:"interfaces,omitempty"`
+	Triggers                *int     `json:"triggers,omitempty"`
+	TriggerDeliveryPolicies *int     `json:"trigger_delivery_policies,omitempty"`
+	Errors                  []string `json:"errors,omitempty"`
+}
+
+func init() {
+	realmsOverviewCmd.Flags().Int("concurrency", 4, "Maximum number of realms inspected at the same time")
+
+	realmsCmd.AddCommand(realmsOverviewCmd)
+}
+
+func realmsOverviewF(command *cobra.Command, args []string) error {
+	concurrency, err := command.Flags().GetInt("concurrency")
+	if err != nil {
+		return err
+	}
+	if concurrency < 1 {
+		return errors.New("--concurrency must be at least 1")
+	}
+
+	realms, err := utils.RequestCandidates(astarteAPIClient)(astarteAPIClient.ListRealms())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	sort.Strings(realms)
+
+	// Credentials are resolved upfront, as they might need prompting for a passphrase
+	overviews := make([]realmOverview, len(realms))
+	realmClients := make([]*client.Client, len(realms))
+	contexts, err := realmContexts()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	for i, realm := range realms {
+		overviews[i].Name = realm
+		for _, contextName := range contexts[realm] {
+			if realmClients[i], err = realmClientForContext(contextName); err == nil {
+				overviews[i].Context = contextName
+				break
+			}
+			overviews[i].Errors = append(overviews[i].Errors, fmt.Sprintf("context %s: %s", contextName, err))
+		}
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency && w < len(realms); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				inspectRealm(&overviews[i], realmClients[i])
+			}
+		}()
+	}
+	for i := range realms {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	t := printer.NewTable()
+	t.AppendHeader(table.Row{"Realm", "Replication", "Device Limit", "Retention", "Context", "Devices (Connected/Total)", "Interfaces", "Triggers", "Policies", "Errors"})
+	for _, o := range overviews {
+		devices := ""
+		if o.TotalDevices != nil {
+			devices = fmt.Sprintf("%d/%d", *o.ConnectedDevices, *o.TotalDevices)
+		}
+		t.AppendRow(table.Row{o.Name, formatReplication(o.realmDetails), formatLimit(o.DeviceRegistrationLimit, false),
+			formatLimit(o.DatastreamMaximumStorageRetention, true), o.Context, devices, formatCount(o.Interfaces),
+			formatCount(o.Triggers), formatCount(o.TriggerDeliveryPolicies), strings.Join(o.Errors, "\n")})
+	}
+	return printer.PrintTable(t, overviews)
+}
+
+// realmContexts returns the contexts of the current cluster, by the realm they point at
+func realmContexts() (map[string][]string, error) {
+	contexts := map[string][]string{}
+	clusterName, err := getClusterNameFromURLs()
+	if err != nil {
+		// No matching cluster, hence no matching context
+		return contexts, nil
+	}
+
+	configDir := config.GetConfigDir()
+	contextNames, err := config.ListContextConfigurations(configDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range contextNames {
+		context, err := config.LoadContextConfiguration(configDir, c)
+		if err != nil {
+			return nil, err
+		}
+		if context.Cluster == clusterName && context.Realm.Name != "" {
+			contexts[context.Realm.Name] = append(contexts[context.Realm.Name], c)
+		}
+	}
+	return contexts, nil
+}
+
+// realmClientForContext returns a client for the realm APIs, authenticated with the credentials of a context
+func realmClientForContext(contextName string) (*client.Client, error) {
+	services := []astarteservices.AstarteService{astarteservices.AppEngine, astarteservices.RealmManagement}
+	token, err := utils.ContextToken(contextName, services)
+	if err != nil {
+		return nil, err
+	}
+	return utils.APIClientWithToken(map[astarteservices.AstarteService]string{
+		astarteservices.AppEngine:       "individual-urls.appengine",
+		astarteservices.RealmManagement: "individual-urls.realm-management",
+	}, token)
+}
+
+// inspectRealm fills o with the details of its realm and, when realmClient is not nil, its stats
+// and counts. Failures are recorded in o, so that the other realms are still reported.
+func inspectRealm(o *realmOverview, realmClient *client.Client) {
+	realm := o.Name
+	getRealmRes, err := runRequest(astarteAPIClient)(astarteAPIClient.GetRealm(realm))
+	if err == nil {
+		o.realmDetails, err = parseRealmDetails(getRealmRes)
+	}
+	if err != nil {
+		o.Errors = append(o.Errors, fmt.Sprintf("details: %s", err))
+		o.Name = realm
+	}
+	if realmClient == nil {
+		return
+	}
+
+	statsRes, err := runRequest(realmClient)(realmClient.GetDevicesStats(realm))
+	if err == nil {
+		var rawStats any
+		if rawStats, err = statsRes.Parse(); err == nil {
+			stats, _ := rawStats.(client.DevicesStats)
+			o.TotalDevices, o.ConnectedDevices = &stats.TotalDevices, &stats.ConnectedDevices
+		}
+	}
+	if err != nil {
+		o.Errors = append(o.Errors, fmt.Sprintf("device stats: %s", err))
+	}
+
+	counts := []struct {
+		name  string
+		count **int
+		list  func(string) (client.AstarteRequest, error)
+	}{
+		{"interfaces", &o.Interfaces, realmClient.ListInterfaces},
+		{"triggers", &o.Triggers, realmClient.ListTriggers},
+		{"trigger delivery policies", &o.TriggerDeliveryPolicies, realmClient.ListTriggerDeliveryPolicies},
+	}
+	for _, c := range counts {
+		names, err := utils.RequestCandidates(realmClient)(c.list(realm))
+		if err != nil {
+			o.Errors = append(o.Errors, fmt.Sprintf("%s: %s", c.name, err))
+			continue
+		}
+		count := len(names)
+		*c.count = &count
+	}
+}
+
+func runRequest(c *client.Client) func(client.AstarteRequest, error) (client.AstarteResponse, error) {
+	return func(req client.AstarteRequest, err error) (client.AstarteResponse, error) {
+		if err != nil {
+			return nil, err
+		}
+		return req.Run(c)
+	}
+}
+
+func formatReplication(details realmDetails) string {
+	if len(details.DatacenterReplicationFactors) > 0 {
+		datacenters := []string{}
+		for k, v := range details.DatacenterReplicationFactors {
+			datacenters = append(datacenters, fmt.Sprintf("%s: %d", k, v))
+		}
+		sort.Strings(datacenters)
+		return strings.Join(datacenters, ", ")
+	}
+	if details.ReplicationFactor > 0 {
+		return fmt.Sprintf("%d", details.ReplicationFactor)
+	}
+	return ""
+}
+
+func formatLimit(limit *int, isDuration bool) string {
+	switch {
+	case limit == nil:
+		return "none"
+	case isDuration:
+		return (time.Duration(*limit) * time.Second).String()
+	default:
+		return fmt.Sprintf("%d", *limit)
+	}
+}
+
+func formatCount(count *int) string {
+	if count == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d", *count)
+}
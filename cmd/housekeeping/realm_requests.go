@@ -0,0 +1,67 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package housekeeping
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/astarte-platform/astarte-go/client"
+	"github.com/astarte-platform/astartectl/utils"
+)
+
+// realmDetails are the details of a realm, including the settings astarte-go doesn't know about yet
+type realmDetails struct {
+	client.RealmDetails
+	// DeviceRegistrationLimit is the maximum number of devices of the realm. Nil when there is no limit
+	DeviceRegistrationLimit *int `json:"device_registration_limit,omitempty"`
+	// DatastreamMaximumStorageRetention is the maximum retention of datastreams, in seconds. Nil when there is no limit
+	DatastreamMaximumStorageRetention *int `json:"datastream_maximum_storage_retention,omitempty"`
+}
+
+// parseRealmDetails parses the details of a realm out of the response to a request getting,
+// creating or updating a realm
+func parseRealmDetails(res client.AstarteResponse) (realmDetails, error) {
+	details := realmDetails{}
+	err, _ := res.Raw(func(r *http.Response) any {
+		body := struct {
+			Data json.RawMessage `json:"data"`
+		}{}
+		contents, err := io.ReadAll(r.Body)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(contents, &body); err != nil {
+			return err
+		}
+		return json.Unmarshal(body.Data, &details)
+	}).(error)
+	return details, err
+}
+
+// The requests below are not supported by astarte-go yet, hence they are built as raw requests
+
+// updateRealm builds a request applying patch, a JSON merge patch, to the settings of realm
+func updateRealm(realm string, patch map[string]interface{}) (client.AstarteRequest, error) {
+	callURL := astarteAPIClient.GetHousekeepingURL().JoinPath("v1", "realms", realm)
+	return utils.NewRawRequest(astarteAPIClient, http.MethodPatch, callURL, patch, "application/merge-patch+json", http.StatusOK)
+}
+
+// deleteRealm builds a request deleting realm, along with all of its data
+func deleteRealm(realm string) (client.AstarteRequest, error) {
+	callURL := astarteAPIClient.GetHousekeepingURL().JoinPath("v1", "realms", realm)
+	return utils.NewRawRequest(astarteAPIClient, http.MethodDelete, callURL, nil, "", http.StatusNoContent)
+}
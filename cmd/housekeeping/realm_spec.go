@@ -0,0 +1,167 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package housekeeping
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+// realmSpec describes a realm to be created. Spec files use the field names of the Housekeeping API,
+// plus the files of the realm key and the bootstrap directory. Paths are relative to the spec file.
+type realmSpec struct {
+	// Name is the name of the realm
+	Name string `json:"realm_name"`
+	// ReplicationFactor is the replication factor of the realm, used with SimpleStrategy replication
+	ReplicationFactor int `json:"replication_factor,omitempty"`
+	// DatacenterReplicationFactors are the replication factors of each datacenter, used with
+	// NetworkTopologyStrategy replication
+	DatacenterReplicationFactors map[string]int `json:"datacenter_replication_factors,omitempty"`
+	// PrivateKeyFile is the path to the PEM private key of the realm. When neither it nor PublicKeyFile
+	// are set, a new private key is generated
+	PrivateKeyFile string `json:"private_key_file,omitempty"`
+	// PublicKeyFile is the path to the PEM public key of the realm
+	PublicKeyFile string `json:"public_key_file,omitempty"`
+	// DeviceRegistrationLimit is the maximum number of devices of the realm. Can be omitted
+	DeviceRegistrationLimit *int `json:"device_registration_limit,omitempty"`
+	// DatastreamMaximumStorageRetention is the maximum retention of datastreams, in seconds. Can be omitted
+	DatastreamMaximumStorageRetention *int `json:"datastream_maximum_storage_retention,omitempty"`
+	// Bootstrap is a directory of manifests, as accepted by astartectl apply, installed in the realm
+	// right after its creation. Can be omitted
+	Bootstrap string `json:"bootstrap,omitempty"`
+}
+
+// loadRealmSpec reads a realm spec file, in YAML or JSON
+func loadRealmSpec(fileName string) (realmSpec, error) {
+	spec := realmSpec{}
+	contents, err := os.ReadFile(fileName)
+	if err != nil {
+		return spec, err
+	}
+	if err := yaml.UnmarshalStrict(contents, &spec); err != nil {
+		return spec, fmt.Errorf("%s: %w", fileName, err)
+	}
+
+	// Paths are relative to the spec file
+	specDir := filepath.Dir(fileName)
+	for _, p := range []*string{&spec.PrivateKeyFile, &spec.PublicKeyFile, &spec.Bootstrap} {
+		if *p != "" && !filepath.IsAbs(*p) {
+			*p = filepath.Join(specDir, *p)
+		}
+	}
+	return spec, nil
+}
+
+// realmSpecFromCommand returns the spec of the realm to create, read from the file given with
+// --filename or built from the flags of command
+func realmSpecFromCommand(command *cobra.Command, args []string) (realmSpec, error) {
+	specFile, err := command.Flags().GetString("filename")
+	if err != nil {
+		return realmSpec{}, err
+	}
+	if specFile != "" {
+		for _, flag := range []string{"realm-private-key", "realm-public-key", "replication-factor", "datacenter-replication"} {
+			if command.Flags().Changed(flag) {
+				return realmSpec{}, fmt.Errorf("--%s can't be used together with --filename", flag)
+			}
+		}
+		spec, err := loadRealmSpec(specFile)
+		if err != nil {
+			return spec, err
+		}
+		// The realm name given as argument overrides the one of the spec
+		if len(args) > 0 {
+			spec.Name = args[0]
+		}
+		if spec.Name == "" {
+			return spec, errors.New("the realm name is missing, set realm_name in the spec file or give it as argument")
+		}
+		return spec, nil
+	}
+
+	if len(args) == 0 {
+		return realmSpec{}, errors.New("a realm name or --filename is required")
+	}
+	spec := realmSpec{Name: args[0]}
+	if spec.PublicKeyFile, err = command.Flags().GetString("realm-public-key"); err != nil {
+		return spec, err
+	}
+	if spec.PrivateKeyFile, err = command.Flags().GetString("realm-private-key"); err != nil {
+		return spec, err
+	}
+	if spec.ReplicationFactor, err = command.Flags().GetInt("replication-factor"); err != nil {
+		return spec, err
+	}
+	datacenterReplications, err := command.Flags().GetStringSlice("datacenter-replication")
+	if err != nil {
+		return spec, err
+	}
+	if len(datacenterReplications) > 0 {
+		spec.DatacenterReplicationFactors = map[string]int{}
+		for _, datacenterString := range datacenterReplications {
+			tokens := strings.Split(datacenterString, ":")
+			if len(tokens) != 2 {
+				errString := "Invalid datacenter replication: " + datacenterString + "."
+				errString += "\nFormat must be <datacenter-name>:<replication-factor>"
+				return spec, errors.New(errString)
+			}
+			datacenter := tokens[0]
+			datacenterReplicationFactor, err := strconv.Atoi(tokens[1])
+			if err != nil {
+				return spec, errors.New("Invalid replication factor " + tokens[1])
+			}
+			spec.DatacenterReplicationFactors[datacenter] = datacenterReplicationFactor
+		}
+	}
+	return spec, nil
+}
+
+// validate checks the consistency of the settings of spec
+func (spec realmSpec) validate() error {
+	if spec.PrivateKeyFile != "" && spec.PublicKeyFile != "" {
+		return errors.New("when passing --realm-private-key, --realm-public-key should not be specified")
+	}
+	if spec.ReplicationFactor > 0 && len(spec.DatacenterReplicationFactors) > 0 {
+		return errors.New("replication-factor and datacenter-replication are mutually exclusive, you only have to specify one")
+	}
+	if spec.Bootstrap != "" && spec.PublicKeyFile != "" {
+		return errors.New("bootstrapping a realm requires its private key, public_key_file can't be used")
+	}
+	for _, limit := range []*int{spec.DeviceRegistrationLimit, spec.DatastreamMaximumStorageRetention} {
+		if limit != nil && *limit < 0 {
+			return errors.New("realm limits can't be negative")
+		}
+	}
+	return nil
+}
+
+// limitsPatch returns the patch setting the limits of spec, which can't be set upon creation
+func (spec realmSpec) limitsPatch() map[string]interface{} {
+	patch := map[string]interface{}{}
+	if spec.DeviceRegistrationLimit != nil {
+		patch["device_registration_limit"] = *spec.DeviceRegistrationLimit
+	}
+	if spec.DatastreamMaximumStorageRetention != nil {
+		patch["datastream_maximum_storage_retention"] = *spec.DatastreamMaximumStorageRetention
+	}
+	return patch
+}
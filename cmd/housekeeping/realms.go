@@ -30,10 +30,13 @@ import (
 	"strconv"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/astarte-platform/astarte-go/auth"
 	"github.com/astarte-platform/astarte-go/client"
 	"github.com/astarte-platform/astartectl/config"
+	"github.com/astarte-platform/astartectl/manifest"
+	"github.com/astarte-platform/astartectl/printer"
 	"github.com/astarte-platform/astartectl/utils"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -43,7 +46,7 @@ import (
 var realmsCmd = &cobra.Command{
 	Use:     "realms",
 	Short:   "Manage realms",
-	Long:    `List, show or create realms in your Astarte instance.`,
+	Long:    `List, show, create, update or delete realms in your Astarte instance.`,
 	Aliases: []string{"realm"},
 }
 
@@ -66,12 +69,53 @@ var realmsShowCmd = &cobra.Command{
 }
 
 var realmsCreateCmd = &cobra.Command{
-	Use:     "create <realm_name>",
-	Short:   "Create realm",
-	Long:    "Create a realm in your Astarte instance. If a private key is provided, an astartectl context with full access is created.",
-	Example: `  astartectl housekeeping realms create myrealm --realm-public-key /path/to/public_key`,
+	Use:   "create [<realm_name>] [-f <realm_spec>]",
+	Short: "Create realm",
+	Long: `Create a realm in your Astarte instance. If a private key is provided, an astartectl context with full access is created.
+
+Instead of flags, the realm can be described by a YAML or JSON spec file given with --filename, such as:
+
+  realm_name: myrealm
+  replication_factor: 1                # or datacenter_replication_factors: {dc1: 3}
+  private_key_file: myrealm.pem        # or public_key_file, when omitted a key is generated
+  device_registration_limit: 100
+  datastream_maximum_storage_retention: 2592000
+  bootstrap: myrealm/
+
+The realm name given as argument overrides the one of the spec. Paths are relative to the spec file.
+The bootstrap directory holds manifests, as accepted by astartectl apply: its interfaces, trigger
+delivery policies and triggers are installed in the realm right after its creation.`,
+	Example: `  astartectl housekeeping realms create myrealm --realm-public-key /path/to/public_key
+  astartectl housekeeping realms create -f realm.yaml`,
+	Args: cobra.RangeArgs(0, 1),
+	RunE: realmsCreateF,
+}
+
+var realmsUpdateCmd = &cobra.Command{
+	Use:   "update <realm_name>",
+	Short: "Update realm",
+	Long: `Update the settings of a realm in your Astarte instance. Only the settings given through flags are
+changed, the others are left untouched.
+
+Limits can be removed by setting them to none. The datastream maximum storage retention is either a
+number of seconds or a duration (e.g. 720h).`,
+	Example: `  astartectl housekeeping realms update myrealm --device-registration-limit 100
+  astartectl housekeeping realms update myrealm --datastream-maximum-storage-retention none`,
+	Args: cobra.ExactArgs(1),
+	RunE: realmsUpdateF,
+}
+
+var realmsDeleteCmd = &cobra.Command{
+	Use:   "delete <realm_name>",
+	Short: "Delete realm",
+	Long: `Delete a realm, along with all of its data, from your Astarte instance. The name of the realm has to
+be typed again to confirm, unless --non-interactive is given.
+
+Local contexts of the current cluster pointing at the realm are deleted too. Deleting realms must be
+enabled in Housekeeping.`,
+	Example: `  astartectl housekeeping realms delete myrealm`,
 	Args:    cobra.ExactArgs(1),
-	RunE:    realmsCreateF,
+	RunE:    realmsDeleteF,
 }
 
 func init() {
@@ -87,6 +131,11 @@ func init() {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
+	realmsCreateCmd.Flags().StringP("filename", "f", "", "Path to a YAML or JSON spec of the realm, used instead of the other flags")
+	if err := realmsCreateCmd.MarkFlagFilename("filename", "yaml", "yml", "json"); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 	realmsCreateCmd.Flags().IntP("replication-factor", "r", 0, `Replication factor for the realm, used with SimpleStrategy replication.`)
 	realmsCreateCmd.Flags().StringSliceP("datacenter-replication", "d", nil,
 		`Replication factor for a datacenter, used with NetworkTopologyStrategy replication.
@@ -96,10 +145,23 @@ You can also specify the flag multiple times instead of separating it with a com
 
 	realmsCreateCmd.PersistentFlags().BoolP("non-interactive", "y", false, "Non-interactive mode. Will answer yes by default to all questions.")
 
+	realmsUpdateCmd.Flags().String("realm-public-key", "", "Path to PEM encoded public key replacing the realm key")
+	if err := realmsUpdateCmd.MarkFlagFilename("realm-public-key"); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	realmsUpdateCmd.Flags().String("device-registration-limit", "", "Maximum number of devices which can be registered in the realm, or none")
+	realmsUpdateCmd.Flags().String("datastream-maximum-storage-retention", "", "Maximum retention of datastream values, as seconds or a duration, or none")
+	realmsUpdateCmd.Flags().BoolP("non-interactive", "y", false, "Non-interactive mode. Will answer yes by default to all questions.")
+
+	realmsDeleteCmd.Flags().BoolP("non-interactive", "y", false, "Non-interactive mode. Deletes the realm without asking for its name.")
+
 	realmsCmd.AddCommand(
 		realmsListCmd,
 		realmsShowCmd,
 		realmsCreateCmd,
+		realmsUpdateCmd,
+		realmsDeleteCmd,
 	)
 }
 
@@ -119,8 +181,7 @@ func realmsListF(command *cobra.Command, args []string) error {
 	}
 	rawRealms, _ := realmsRes.Parse()
 	realms, _ := rawRealms.([]string)
-	fmt.Println(realms)
-	return nil
+	return printer.PrintList("Realm", realms)
 }
 
 func realmsShowF(command *cobra.Command, args []string) error {
@@ -139,38 +200,228 @@ func realmsShowF(command *cobra.Command, args []string) error {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
-	realmDetails, _ := getRealmDetailsRes.Parse()
+	realmDetails, err := parseRealmDetails(getRealmDetailsRes)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 
-	fmt.Printf("%+v\n", realmDetails)
-	return nil
+	return printer.Print(realmDetails, func() { prettyPrintRealmDetails(realmDetails) })
 }
 
-func realmsCreateF(command *cobra.Command, args []string) error {
+func prettyPrintRealmDetails(realmDetails realmDetails) {
+	w := tabwriter.NewWriter(printer.Out, 0, 0, 4, ' ', 0)
+	fmt.Fprintf(w, "Realm name:\t%s\n", realmDetails.Name)
+	if realmDetails.ReplicationClass != "" {
+		fmt.Fprintf(w, "Replication class:\t%s\n", realmDetails.ReplicationClass)
+	}
+	if len(realmDetails.DatacenterReplicationFactors) > 0 {
+		fmt.Fprint(w, "Datacenter replications:")
+		for k, v := range realmDetails.DatacenterReplicationFactors {
+			fmt.Fprintf(w, "\t%s: %d\n", k, v)
+		}
+	} else if realmDetails.ReplicationFactor > 0 {
+		fmt.Fprintf(w, "Replication factor:\t%d\n", realmDetails.ReplicationFactor)
+	}
+	if realmDetails.DeviceRegistrationLimit != nil {
+		fmt.Fprintf(w, "Device registration limit:\t%d\n", *realmDetails.DeviceRegistrationLimit)
+	}
+	if realmDetails.DatastreamMaximumStorageRetention != nil {
+		fmt.Fprintf(w, "Datastream maximum storage retention:\t%s\n", time.Duration(*realmDetails.DatastreamMaximumStorageRetention)*time.Second)
+	}
+	fmt.Fprintf(w, "JWT public key:\t\n%s\n", strings.TrimSpace(realmDetails.JwtPublicKeyPEM))
+	w.Flush()
+}
+
+func realmsUpdateF(command *cobra.Command, args []string) error {
 	realm := args[0]
-	publicKey, err := command.Flags().GetString("realm-public-key")
+	y, err := command.Flags().GetBool("non-interactive")
 	if err != nil {
 		return err
 	}
-	privateKey, err := command.Flags().GetString("realm-private-key")
+
+	// Patch only the given settings, null removes a limit
+	patch := map[string]interface{}{}
+	if command.Flags().Changed("realm-public-key") {
+		publicKey, err := command.Flags().GetString("realm-public-key")
+		if err != nil {
+			return err
+		}
+		publicKeyContent, err := os.ReadFile(publicKey)
+		if err != nil {
+			return err
+		}
+		patch["jwt_public_key_pem"] = string(publicKeyContent)
+	}
+	if command.Flags().Changed("device-registration-limit") {
+		limit, err := command.Flags().GetString("device-registration-limit")
+		if err != nil {
+			return err
+		}
+		if patch["device_registration_limit"], err = parseRealmLimit(limit, false); err != nil {
+			return fmt.Errorf("invalid device registration limit: %w", err)
+		}
+	}
+	if command.Flags().Changed("datastream-maximum-storage-retention") {
+		retention, err := command.Flags().GetString("datastream-maximum-storage-retention")
+		if err != nil {
+			return err
+		}
+		if patch["datastream_maximum_storage_retention"], err = parseRealmLimit(retention, true); err != nil {
+			return fmt.Errorf("invalid datastream maximum storage retention: %w", err)
+		}
+	}
+	if len(patch) == 0 {
+		return errors.New("nothing to update, specify at least one of --realm-public-key, --device-registration-limit or --datastream-maximum-storage-retention")
+	}
+
+	updateRealmReq, err := updateRealm(realm, patch)
 	if err != nil {
-		return err
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	utils.MaybeCurlAndExit(updateRealmReq, astarteAPIClient)
+
+	if !y && !utils.IsDryRun() {
+		fmt.Printf("Will update Astarte Realm %s with following settings:\n", realm)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+		for _, field := range []string{"jwt_public_key_pem", "device_registration_limit", "datastream_maximum_storage_retention"} {
+			value, ok := patch[field]
+			switch {
+			case !ok:
+				continue
+			case value == nil:
+				fmt.Fprintf(w, "%s:\tnone\n", field)
+			case field == "jwt_public_key_pem":
+				fmt.Fprintf(w, "%s:\t\n%s\n", field, strings.TrimSpace(value.(string)))
+			default:
+				fmt.Fprintf(w, "%s:\t%v\n", field, value)
+			}
+		}
+		w.Flush()
+		fmt.Println()
+		if ok, err := utils.AskForConfirmation("Do you want to continue?"); !ok || err != nil {
+			os.Exit(0)
+		}
+	}
+
+	utils.MaybeDryRunAndExit(updateRealmReq, astarteAPIClient)
+
+	updateRealmRes, err := updateRealmReq.Run(astarteAPIClient)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	realmDetails, err := parseRealmDetails(updateRealmRes)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	return printer.Print(realmDetails, func() {
+		fmt.Fprintf(printer.Out, "Realm %s updated successfully!\n\n", realm)
+		prettyPrintRealmDetails(realmDetails)
+	})
+}
+
+// parseRealmLimit parses a realm limit, returning nil for none. Durations are returned in seconds
+func parseRealmLimit(value string, isDuration bool) (interface{}, error) {
+	if value == "none" {
+		return nil, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil && isDuration {
+		var duration time.Duration
+		if duration, err = time.ParseDuration(value); err == nil {
+			limit = int(duration.Seconds())
+		}
+	}
+	if err != nil {
+		return nil, err
 	}
-	if privateKey != "" && publicKey != "" {
-		return errors.New("when passing --realm-private-key, --realm-public-key should not be specified")
+	if limit < 0 {
+		return nil, errors.New("it can't be negative")
 	}
+	return limit, nil
+}
 
-	replicationFactor, err := command.Flags().GetInt("replication-factor")
+func realmsDeleteF(command *cobra.Command, args []string) error {
+	realm := args[0]
+	y, err := command.Flags().GetBool("non-interactive")
 	if err != nil {
 		return err
 	}
 
-	datacenterReplications, err := command.Flags().GetStringSlice("datacenter-replication")
+	contexts, err := contextsUsingRealm(realm)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	deleteRealmReq, err := deleteRealm(realm)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	utils.MaybeCurlAndExit(deleteRealmReq, astarteAPIClient)
+
+	if !y && !utils.IsDryRun() {
+		fmt.Printf("Will delete Astarte Realm %s.\n", realm)
+		if len(contexts) > 0 {
+			fmt.Printf("Will delete Astarte Contexts %s.\n", strings.Join(contexts, ", "))
+		}
+		fmt.Println("WARNING: This operation is NOT REVERSIBLE and ALL DATA WILL BE LOST!!!")
+		confirmation, _ := utils.PromptChoice("To continue, please enter the exact name of the realm you are deleting:", "", true, false)
+		if confirmation != realm {
+			fmt.Fprintln(os.Stderr, "Aborting.")
+			os.Exit(1)
+		}
+	}
+
+	utils.MaybeDryRunAndExit(deleteRealmReq, astarteAPIClient)
+
+	if _, err := deleteRealmReq.Run(astarteAPIClient); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Printf("Realm %s deleted successfully\n", realm)
+
+	configDir := config.GetConfigDir()
+	baseConfig, _ := config.LoadBaseConfiguration(configDir)
+	for _, contextName := range contexts {
+		if err := config.DeleteContextConfiguration(configDir, contextName); err != nil {
+			fmt.Fprintf(os.Stderr, "Could not delete context %s: %s\n", contextName, err)
+			continue
+		}
+		fmt.Printf("Context %s deleted successfully\n", contextName)
+		if contextName == baseConfig.CurrentContext {
+			config.UpdateBaseConfigWithContext(configDir, "")
+		}
+	}
+
+	return nil
+}
+
+func realmsCreateF(command *cobra.Command, args []string) error {
+	spec, err := realmSpecFromCommand(command, args)
 	if err != nil {
 		return err
 	}
+	if err := spec.validate(); err != nil {
+		return err
+	}
+	realm := spec.Name
+	privateKey := spec.PrivateKeyFile
+	publicKey := spec.PublicKeyFile
+	replicationFactor := spec.ReplicationFactor
+	datacenterReplicationFactors := spec.DatacenterReplicationFactors
 
-	if replicationFactor > 0 && len(datacenterReplications) > 0 {
-		return errors.New("replication-factor and datacenter-replication are mutually exclusive, you only have to specify one")
+	// Load the bootstrap manifests right away, so that mistakes are found before creating the realm
+	var bootstrapResources []manifest.Resource
+	if spec.Bootstrap != "" {
+		if bootstrapResources, err = manifest.LoadPaths([]string{spec.Bootstrap}); err != nil {
+			return err
+		}
 	}
 
 	createContext := true
@@ -181,23 +432,8 @@ func realmsCreateF(command *cobra.Command, args []string) error {
 	if privateKey == "" && publicKey != "" {
 		createContext = false
 	}
-
-	datacenterReplicationFactors := make(map[string]int)
-	if len(datacenterReplications) > 0 {
-		for _, datacenterString := range datacenterReplications {
-			tokens := strings.Split(datacenterString, ":")
-			if len(tokens) != 2 {
-				errString := "Invalid datacenter replication: " + datacenterString + "."
-				errString += "\nFormat must be <datacenter-name>:<replication-factor>"
-				return errors.New(errString)
-			}
-			datacenter := tokens[0]
-			datacenterReplicationFactor, err := strconv.Atoi(tokens[1])
-			if err != nil {
-				return errors.New("Invalid replication factor " + tokens[1])
-			}
-			datacenterReplicationFactors[datacenter] = datacenterReplicationFactor
-		}
+	if len(bootstrapResources) > 0 && !createContext {
+		return errors.New("bootstrapping a realm requires creating its context: configure a cluster matching the Astarte URL, and don't supply only a public key")
 	}
 
 	urlString := viper.GetString("url")
@@ -230,9 +466,18 @@ func realmsCreateF(command *cobra.Command, args []string) error {
 		}
 		fmt.Fprintf(w, "Replication factor:\t%d\n", printedReplicationFactor)
 	}
+	if spec.DeviceRegistrationLimit != nil {
+		fmt.Fprintf(w, "Device registration limit:\t%d\n", *spec.DeviceRegistrationLimit)
+	}
+	if spec.DatastreamMaximumStorageRetention != nil {
+		fmt.Fprintf(w, "Datastream maximum storage retention:\t%s\n", time.Duration(*spec.DatastreamMaximumStorageRetention)*time.Second)
+	}
 	if createContext {
 		fmt.Fprintf(w, "Astarte Context:\t%s\n", contextName)
 	}
+	if spec.Bootstrap != "" {
+		fmt.Fprintf(w, "Bootstrap:\t%d resources from %s\n", len(bootstrapResources), spec.Bootstrap)
+	}
 	w.Flush()
 	fmt.Println()
 
@@ -255,7 +500,7 @@ func realmsCreateF(command *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	if !y {
+	if !y && !utils.IsDryRun() {
 		if ok, err := utils.AskForConfirmation("Do you want to continue?"); !ok || err != nil {
 			os.Exit(0)
 		}
@@ -339,6 +584,7 @@ func realmsCreateF(command *cobra.Command, args []string) error {
 	}
 
 	utils.MaybeCurlAndExit(createRealmReq, astarteAPIClient)
+	utils.MaybeDryRunAndExit(createRealmReq, astarteAPIClient)
 
 	createRealmRes, err := createRealmReq.Run(astarteAPIClient)
 	if err != nil {
@@ -349,18 +595,49 @@ func realmsCreateF(command *cobra.Command, args []string) error {
 
 	fmt.Printf("Realm %s created successfully!\n", realm)
 
-	// If we're not creating a context, return (and print the key for reference)
-	if !createContext {
-		if privateKey == "" && publicKey == "" {
-			fmt.Println()
-			fmt.Println("This is your Realm's private key. Make sure you store it somewhere safe.")
-			fmt.Println()
-			fmt.Println(string(privateKeyContent))
+	// Limits can't be set upon creation, and a failure here must not prevent saving the key
+	failed := false
+	if patch := spec.limitsPatch(); len(patch) > 0 {
+		updateRealmReq, err := updateRealm(realm, patch)
+		if err == nil {
+			_, err = updateRealmReq.Run(astarteAPIClient)
 		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not set the limits of realm %s: %s\n", realm, err)
+			failed = true
+		}
+	}
+
+	contextSaved := false
+	if createContext {
+		contextSaved = saveRealmContext(realm, contextName, clusterConfigurationName, privateKey, privateKeyContent)
+	} else if privateKey == "" && publicKey == "" {
+		// If we're not creating a context, print the key for reference
+		fmt.Println()
+		fmt.Println("This is your Realm's private key. Make sure you store it somewhere safe.")
+		fmt.Println()
+		fmt.Println(string(privateKeyContent))
+	}
 
-		return nil
+	if len(bootstrapResources) > 0 && !failed {
+		if !contextSaved {
+			fmt.Fprintf(os.Stderr, "Could not bootstrap realm %s: its context could not be saved\n", realm)
+			failed = true
+		} else if err := bootstrapRealm(realm, contextName, bootstrapResources); err != nil {
+			fmt.Fprintf(os.Stderr, "Could not bootstrap realm %s: %s\n", realm, err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
 	}
 
+	return nil
+}
+
+// saveRealmContext saves a context with full access to a newly created realm, and makes it the current one.
+// It returns whether the context was saved.
+func saveRealmContext(realm, contextName, clusterConfigurationName, privateKey string, privateKeyContent []byte) bool {
 	realmContext := config.RealmConfiguration{
 		Name: realm,
 	}
@@ -382,14 +659,28 @@ func realmsCreateF(command *cobra.Command, args []string) error {
 			fmt.Fprintln(os.Stderr, "Dumping private key for reference")
 			fmt.Println(string(privateKeyContent))
 		}
-	} else {
-		fmt.Printf("Context %s created successfully\n", contextName)
+		return false
+	}
+	fmt.Printf("Context %s created successfully\n", contextName)
+
+	// Now set the current context to the new one
+	config.UpdateBaseConfigWithContext(configDir, contextName)
+	return true
+}
 
-		// Now set the current context to the new one
-		config.UpdateBaseConfigWithContext(configDir, contextName)
+// bootstrapRealm installs resources in a newly created realm, authenticating through the context
+// saved for it, like later commands will
+func bootstrapRealm(realm, contextName string, resources []manifest.Resource) error {
+	realmClient, err := realmClientForContext(contextName)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	plan, err := manifest.NewPlan(realmClient, realm, resources, manifest.Options{})
+	if err != nil {
+		return err
+	}
+	return manifest.Apply(realmClient, plan, os.Stdout)
 }
 
 func getPrivateKeyPEMBytes(key *ecdsa.PrivateKey) ([]byte, error) {
@@ -0,0 +1,283 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package housekeeping
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/astarte-platform/astarte-go/astarteservices"
+	"github.com/astarte-platform/astarte-go/auth"
+	"github.com/astarte-platform/astartectl/config"
+	"github.com/astarte-platform/astartectl/utils"
+	"github.com/spf13/cobra"
+)
+
+var realmsRotateKeyCmd = &cobra.Command{
+	Use:   "rotate-key <realm_name>",
+	Short: "Rotate the key of a realm",
+	Long: `Replace the JWT public key of a realm, and update the local contexts using it.
+
+A new private key is generated, unless one is given with --realm-private-key. Once the realm public key
+is updated, a token signed with the new private key is checked against Realm Management: if it is not
+accepted, the previous public key is restored.
+
+Every context of the current cluster pointing at the realm then gets the new private key, replacing any
+token. A copy of each context, with its previous key, is saved in the backups directory of the config
+directory.`,
+	Example: `  astartectl housekeeping realms rotate-key myrealm
+  astartectl housekeeping realms rotate-key myrealm --realm-private-key /path/to/new_private_key`,
+	Args: cobra.ExactArgs(1),
+	RunE: realmsRotateKeyF,
+}
+
+func init() {
+	realmsRotateKeyCmd.Flags().String("realm-private-key", "", "Path to the PEM encoded private key replacing the realm key. If not specified, a new one is generated")
+	if err := realmsRotateKeyCmd.MarkFlagFilename("realm-private-key"); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	realmsRotateKeyCmd.Flags().BoolP("non-interactive", "y", false, "Non-interactive mode. Will answer yes by default to all questions.")
+
+	realmsCmd.AddCommand(realmsRotateKeyCmd)
+}
+
+func realmsRotateKeyF(command *cobra.Command, args []string) error {
+	realm := args[0]
+	privateKey, err := command.Flags().GetString("realm-private-key")
+	if err != nil {
+		return err
+	}
+	y, err := command.Flags().GetBool("non-interactive")
+	if err != nil {
+		return err
+	}
+
+	// The current public key is restored if the new one doesn't work
+	getRealmCall, err := astarteAPIClient.GetRealm(realm)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	getRealmRes, err := getRealmCall.Run(astarteAPIClient)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	realmDetails, err := parseRealmDetails(getRealmRes)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not read the current public key of realm %s: %s\n", realm, err)
+		os.Exit(1)
+	}
+	if strings.TrimSpace(realmDetails.JwtPublicKeyPEM) == "" {
+		// Without it, a failed rotation could not be rolled back
+		fmt.Fprintf(os.Stderr, "Realm %s has no current public key, refusing to rotate it\n", realm)
+		os.Exit(1)
+	}
+
+	contexts, err := contextsUsingRealm(realm)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	privateKeyContent, publicKeyContent, err := loadOrGenerateRealmKey(privateKey)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	updateRealmReq, err := updateRealm(realm, map[string]interface{}{"jwt_public_key_pem": string(publicKeyContent)})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	utils.MaybeCurlAndExit(updateRealmReq, astarteAPIClient)
+
+	fmt.Println("Will rotate the key of Astarte Realm with following parameters:")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+	fmt.Fprintf(w, "Realm name:\t%s\n", realm)
+	if privateKey != "" {
+		fmt.Fprintf(w, "New private key:\t%s\n", privateKey)
+	} else {
+		fmt.Fprintf(w, "New private key:\tgenerated\n")
+	}
+	if len(contexts) > 0 {
+		fmt.Fprintf(w, "Astarte Contexts to update:\t%s\n", strings.Join(contexts, ", "))
+	}
+	w.Flush()
+	fmt.Println()
+	if len(contexts) == 0 {
+		fmt.Println("No Astarte context uses this realm in the current cluster, none will be updated.")
+		fmt.Println()
+	}
+
+	if !y && !utils.IsDryRun() {
+		if ok, err := utils.AskForConfirmation("Do you want to continue?"); !ok || err != nil {
+			os.Exit(0)
+		}
+	}
+
+	utils.MaybeDryRunAndExit(updateRealmReq, astarteAPIClient)
+
+	if _, err := updateRealmReq.Run(astarteAPIClient); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Printf("Public key of realm %s updated.\n", realm)
+
+	if err := verifyRealmKey(realm, privateKeyContent); err != nil {
+		fmt.Fprintf(os.Stderr, "A token signed with the new key was not accepted: %s\n", err)
+		restoreRealmReq, err := updateRealm(realm, map[string]interface{}{"jwt_public_key_pem": realmDetails.JwtPublicKeyPEM})
+		if err == nil {
+			_, err = restoreRealmReq.Run(astarteAPIClient)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not restore the previous public key: %s\n", err)
+			printRealmPrivateKey(privateKey, privateKeyContent)
+			os.Exit(1)
+		}
+		fmt.Fprintln(os.Stderr, "The previous public key was restored.")
+		os.Exit(1)
+	}
+	fmt.Println("A token signed with the new key is accepted.")
+
+	configDir := config.GetConfigDir()
+	failed := false
+	for _, contextName := range contexts {
+		if err := updateContextRealmKey(configDir, contextName, privateKeyContent); err != nil {
+			fmt.Fprintf(os.Stderr, "Could not update context %s: %s\n", contextName, err)
+			failed = true
+		}
+	}
+	if len(contexts) == 0 || failed {
+		printRealmPrivateKey(privateKey, privateKeyContent)
+	}
+	if failed {
+		os.Exit(1)
+	}
+
+	return nil
+}
+
+// contextsUsingRealm returns the contexts of the current cluster pointing at realm
+func contextsUsingRealm(realm string) ([]string, error) {
+	clusterName, err := getClusterNameFromURLs()
+	if err != nil {
+		// No matching cluster, hence no matching context
+		return nil, nil
+	}
+
+	configDir := config.GetConfigDir()
+	contextNames, err := config.ListContextConfigurations(configDir)
+	if err != nil {
+		return nil, err
+	}
+	contexts := []string{}
+	for _, c := range contextNames {
+		context, err := config.LoadContextConfiguration(configDir, c)
+		if err != nil {
+			return nil, err
+		}
+		if context.Cluster == clusterName && context.Realm.Name == realm {
+			contexts = append(contexts, c)
+		}
+	}
+	return contexts, nil
+}
+
+// loadOrGenerateRealmKey returns the PEM private key read from privateKey, or a newly generated
+// one when it is empty, along with its PEM public key
+func loadOrGenerateRealmKey(privateKey string) ([]byte, []byte, error) {
+	var privateKeyContent []byte
+	var key interface{}
+	var err error
+	if privateKey != "" {
+		if privateKeyContent, err = os.ReadFile(privateKey); err != nil {
+			return nil, nil, err
+		}
+		if key, err = auth.ParsePrivateKeyFromPEM(privateKeyContent); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		if privateKeyContent, err = getPrivateKeyPEMBytes(ecdsaKey); err != nil {
+			return nil, nil, err
+		}
+		key = ecdsaKey
+	}
+
+	publicKeyContent, err := getPublicKeyPEMBytesFromPrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return privateKeyContent, publicKeyContent, nil
+}
+
+// verifyRealmKey checks that Realm Management accepts a token for realm signed with privateKey
+func verifyRealmKey(realm string, privateKey []byte) error {
+	realmManagementClient, err := utils.APIClientWithKey(
+		map[astarteservices.AstarteService]string{astarteservices.RealmManagement: "individual-urls.realm-management"}, privateKey)
+	if err != nil {
+		return err
+	}
+	listInterfacesCall, err := realmManagementClient.ListInterfaces(realm)
+	if err != nil {
+		return err
+	}
+	_, err = listInterfacesCall.Run(realmManagementClient)
+	return err
+}
+
+// updateContextRealmKey replaces the realm key of a context with privateKey, after saving a backup of it
+func updateContextRealmKey(configDir, contextName string, privateKey []byte) error {
+	context, err := config.LoadContextConfiguration(configDir, contextName)
+	if err != nil {
+		return err
+	}
+	backupPath, err := config.BackupContextConfiguration(configDir, contextName)
+	if err != nil {
+		return err
+	}
+
+	context.Realm.Key = base64.StdEncoding.EncodeToString(privateKey)
+	// Tokens were signed with the previous key
+	context.Realm.Token = ""
+	if err := config.SaveContextConfiguration(configDir, contextName, context, true); err != nil {
+		return err
+	}
+	fmt.Printf("Context %s updated, its previous configuration was saved to %s\n", contextName, backupPath)
+	return nil
+}
+
+// printRealmPrivateKey prints a generated private key which was not saved in any context
+func printRealmPrivateKey(privateKey string, privateKeyContent []byte) {
+	if privateKey != "" {
+		return
+	}
+	fmt.Println()
+	fmt.Println("This is your Realm's new private key. Make sure you store it somewhere safe.")
+	fmt.Println()
+	fmt.Println(string(privateKeyContent))
+}
@@ -79,6 +79,7 @@ func agentRegisterF(command *cobra.Command, args []string) error {
 	}
 
 	utils.MaybeCurlAndExit(registerDeviceCall, astarteAPIClient)
+	utils.MaybeDryRunAndExit(registerDeviceCall, astarteAPIClient)
 
 	registerDeviceRes, err := registerDeviceCall.Run(astarteAPIClient)
 	if err != nil {
@@ -121,7 +122,7 @@ func agentUnregisterF(command *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("Will unregister device %s from realm %s.\n", deviceID, realm)
-	if !nonInteractive {
+	if !nonInteractive && !utils.IsDryRun() {
 		confirmation, err := utils.AskForConfirmation("Do you want to continue?")
 		if err != nil {
 			fmt.Fprintln(os.Stderr, err)
@@ -139,6 +140,7 @@ func agentUnregisterF(command *cobra.Command, args []string) error {
 	}
 
 	utils.MaybeCurlAndExit(unregisterDeviceCall, astarteAPIClient)
+	utils.MaybeDryRunAndExit(unregisterDeviceCall, astarteAPIClient)
 
 	unregisterDeviceRes, err := unregisterDeviceCall.Run(astarteAPIClient)
 	if err != nil {
@@ -0,0 +1,53 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pairing
+
+import (
+	"github.com/astarte-platform/astarte-go/astarteservices"
+	"github.com/astarte-platform/astarte-go/client"
+	"github.com/astarte-platform/astartectl/utils"
+)
+
+func init() {
+	agentUnregisterCmd.ValidArgsFunction = utils.CompleteArgs(completeDeviceIDs)
+}
+
+// completeDeviceIDs returns the Device IDs of the devices in the realm, through AppEngine
+func completeDeviceIDs(args []string) ([]string, error) {
+	// The pairing client can't reach AppEngine, use a dedicated one with the same credentials
+	appEngineClient, err := utils.APICommandSetup(
+		map[astarteservices.AstarteService]string{astarteservices.AppEngine: "individual-urls.appengine"}, "realm.key", "realm.key-file")
+	if err != nil {
+		return nil, err
+	}
+	paginator, err := appEngineClient.GetDeviceListPaginator(realm, 100, client.DeviceIDFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := []string{}
+	for paginator.HasNextPage() && len(candidates) < utils.MaxCompletedDevices {
+		nextPageCall, err := paginator.GetNextPage()
+		if err != nil {
+			return nil, err
+		}
+		page, err := utils.RequestCandidates(appEngineClient)(nextPageCall, nil)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, page...)
+	}
+	return candidates, nil
+}
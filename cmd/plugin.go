@@ -0,0 +1,237 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/astarte-platform/astartectl/config"
+	"github.com/astarte-platform/astartectl/printer"
+	"github.com/astarte-platform/astartectl/utils"
+	"github.com/jedib0t/go-pretty/table"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const pluginPrefix = "astartectl-"
+
+var pluginCmd = &cobra.Command{
+	Use:   "plugin",
+	Short: "Manage astartectl plugins",
+	Long: `Manage astartectl plugins.
+
+A plugin is any executable in PATH named astartectl-<name>. Running "astartectl <name>" runs the
+plugin, passing it all remaining arguments. The resolved configuration is exported to the plugin
+through the following environment variables:
+
+  ASTARTECTL                   Path to the astartectl executable
+  ASTARTE_CONFIG_DIR           The configuration directory
+  ASTARTECTL_CONFIG            The configuration files, when used instead of the configuration directory
+  ASTARTE_CONTEXT              The name of the context in use, if any
+  ASTARTE_URL                  The base URL of the Astarte APIs
+  ASTARTE_<SERVICE>_URL        Individual API URLs, when configured (e.g. ASTARTE_APPENGINE_URL)
+  ASTARTE_REALM                The name of the realm
+  ASTARTE_TOKEN                A token valid for all realm APIs, when credentials are available
+
+Plugins needing a Housekeeping token can run "$ASTARTECTL config contexts token $ASTARTE_CONTEXT --service housekeeping".
+Built-in commands always take precedence over plugins.`,
+}
+
+var pluginListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List available plugins",
+	Long:    `List all astartectl-<name> executables found in PATH.`,
+	Example: `  astartectl plugin list`,
+	Args:    cobra.ExactArgs(0),
+	RunE:    pluginListF,
+	Aliases: []string{"ls"},
+}
+
+type pluginInfo struct {
+	Name  string `json:"name"`
+	Path  string `json:"path"`
+	Notes string `json:"notes,omitempty"`
+}
+
+func init() {
+	pluginCmd.AddCommand(pluginListCmd)
+	rootCmd.AddCommand(pluginCmd)
+}
+
+func pluginListF(command *cobra.Command, args []string) error {
+	plugins := []pluginInfo{}
+	found := map[string]string{}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			// PATH often contains directories which do not exist
+			continue
+		}
+		for _, e := range entries {
+			if !strings.HasPrefix(e.Name(), pluginPrefix) || !isExecutable(filepath.Join(dir, e.Name())) {
+				continue
+			}
+			plugin := pluginInfo{Name: pluginName(e.Name()), Path: filepath.Join(dir, e.Name())}
+			if isBuiltinCommand(plugin.Name) {
+				plugin.Notes = "overridden by a built-in command"
+			} else if first, ok := found[plugin.Name]; ok {
+				plugin.Notes = "shadowed by " + first
+			} else {
+				found[plugin.Name] = plugin.Path
+			}
+			plugins = append(plugins, plugin)
+		}
+	}
+
+	t := printer.NewTable()
+	t.AppendHeader(table.Row{"Name", "Path", "Notes"})
+	for _, p := range plugins {
+		t.AppendRow(table.Row{p.Name, p.Path, p.Notes})
+	}
+	return printer.PrintTable(t, plugins)
+}
+
+// maybeRunPlugin runs a plugin when args refer to a command which is not built in and an
+// astartectl-<name> executable exists in PATH. It returns false when args should be handled
+// by the root command, and never returns otherwise.
+func maybeRunPlugin(args []string) bool {
+	// Parse global flags only, stopping at the first positional argument
+	flags := pflag.NewFlagSet("astartectl", pflag.ContinueOnError)
+	flags.AddFlagSet(rootCmd.PersistentFlags())
+	flags.SetInterspersed(false)
+	flags.ParseErrorsWhitelist.UnknownFlags = true
+	flags.Usage = func() {}
+	if err := flags.Parse(args); err != nil || flags.NArg() == 0 {
+		return false
+	}
+
+	name := flags.Arg(0)
+	if isBuiltinCommand(name) {
+		return false
+	}
+	pluginPath, err := exec.LookPath(pluginPrefix + name)
+	if err != nil {
+		return false
+	}
+
+	initConfig()
+
+	plugin := exec.Command(pluginPath, flags.Args()[1:]...)
+	plugin.Env = append(os.Environ(), pluginEnvironment()...)
+	plugin.Stdin = os.Stdin
+	plugin.Stdout = os.Stdout
+	plugin.Stderr = os.Stderr
+	if err := plugin.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+	return true
+}
+
+func pluginEnvironment() []string {
+	// Paths are made absolute, as plugins might change their working directory
+	env := []string{"ASTARTE_CONFIG_DIR=" + absolutePath(config.GetConfigDir())}
+	if files := config.GetConfigFiles(); len(files) > 0 {
+		absoluteFiles := make([]string, 0, len(files))
+		for _, f := range files {
+			absoluteFiles = append(absoluteFiles, absolutePath(f))
+		}
+		env = append(env, "ASTARTECTL_CONFIG="+strings.Join(absoluteFiles, string(os.PathListSeparator)))
+	}
+	if executable, err := os.Executable(); err == nil {
+		env = append(env, "ASTARTECTL="+executable)
+	}
+	contextName := config.CurrentContextName()
+	if contextName != "" {
+		env = append(env, "ASTARTE_CONTEXT="+contextName)
+	}
+
+	variables := map[string]string{
+		"ASTARTE_URL":                  "url",
+		"ASTARTE_APPENGINE_URL":        "individual-urls.appengine",
+		"ASTARTE_FLOW_URL":             "individual-urls.flow",
+		"ASTARTE_HOUSEKEEPING_URL":     "individual-urls.housekeeping",
+		"ASTARTE_PAIRING_URL":          "individual-urls.pairing",
+		"ASTARTE_REALM_MANAGEMENT_URL": "individual-urls.realm-management",
+		"ASTARTE_REALM":                "realm.name",
+	}
+	for envVar, key := range variables {
+		if value := viper.GetString(key); value != "" {
+			env = append(env, envVar+"="+value)
+		}
+	}
+
+	token := viper.GetString("token")
+	if token == "" && contextName != "" && viper.GetString("realm.name") != "" {
+		var err error
+		if token, err = utils.ContextToken(contextName, utils.RealmServices); err != nil {
+			fmt.Fprintf(os.Stderr, "warn: Could not get a token for context %s: %s\n", contextName, err)
+		}
+	}
+	if token != "" {
+		env = append(env, "ASTARTE_TOKEN="+token)
+	}
+
+	return env
+}
+
+func isBuiltinCommand(name string) bool {
+	// Commands cobra adds on its own when executing
+	switch name {
+	case "help", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return true
+	}
+	_, _, err := rootCmd.Find([]string{name})
+	return err == nil
+}
+
+func pluginName(fileName string) string {
+	name := strings.TrimPrefix(fileName, pluginPrefix)
+	if runtime.GOOS == "windows" {
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return name
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	return info.Mode()&0111 != 0
+}
+
+// absolutePath returns p as an absolute path, or as it is if that fails
+func absolutePath(p string) string {
+	if abs, err := filepath.Abs(p); err == nil {
+		return abs
+	}
+	return p
+}
@@ -0,0 +1,45 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package realm
+
+import (
+	"github.com/astarte-platform/astartectl/utils"
+)
+
+func init() {
+	interfacesVersionsCmd.ValidArgsFunction = utils.CompleteArgs(completeInterfaceNames)
+	interfacesShowCmd.ValidArgsFunction = utils.CompleteArgs(completeInterfaceNames, completeInterfaceMajors)
+	interfacesDeleteCmd.ValidArgsFunction = utils.CompleteArgs(completeInterfaceNames)
+	triggersShowCmd.ValidArgsFunction = utils.CompleteArgs(completeTriggerNames)
+	triggersDeleteCmd.ValidArgsFunction = utils.CompleteArgs(completeTriggerNames)
+	triggersPoliciesShowCmd.ValidArgsFunction = utils.CompleteArgs(completeTriggerPolicyNames)
+	triggersPoliciesDeleteCmd.ValidArgsFunction = utils.CompleteArgs(completeTriggerPolicyNames)
+}
+
+func completeInterfaceNames(args []string) ([]string, error) {
+	return utils.RequestCandidates(astarteAPIClient)(astarteAPIClient.ListInterfaces(realm))
+}
+
+func completeInterfaceMajors(args []string) ([]string, error) {
+	return utils.RequestCandidates(astarteAPIClient)(astarteAPIClient.ListInterfaceMajorVersions(realm, args[0]))
+}
+
+func completeTriggerNames(args []string) ([]string, error) {
+	return utils.RequestCandidates(astarteAPIClient)(astarteAPIClient.ListTriggers(realm))
+}
+
+func completeTriggerPolicyNames(args []string) ([]string, error) {
+	return utils.RequestCandidates(astarteAPIClient)(astarteAPIClient.ListTriggerDeliveryPolicies(realm))
+}
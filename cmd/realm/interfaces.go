@@ -22,7 +22,10 @@ import (
 	"strconv"
 
 	"github.com/astarte-platform/astarte-go/interfaces"
+	"github.com/astarte-platform/astartectl/interfacediff"
+	"github.com/astarte-platform/astartectl/printer"
 	"github.com/astarte-platform/astartectl/utils"
+	"github.com/jedib0t/go-pretty/table"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -91,7 +94,10 @@ var interfacesUpdateCmd = &cobra.Command{
 	Long: `Update the given interface in the realm.
 <interface_file> must be a path to a JSON file containing a valid Astarte interface.
 
-The name and major version of the interface are read from the interface file.`,
+The name and major version of the interface are read from the interface file.
+Before updating, the interface file is checked against the installed interface following the
+evolution rules of Astarte (see 'astartectl utils interfaces check-compat'), unless
+--skip-compat-check is set.`,
 	Example: `  astartectl realm-management interfaces update com.my.Interface.json`,
 	Args:    cobra.ExactArgs(1),
 	RunE:    interfacesUpdateF,
@@ -102,7 +108,10 @@ var interfacesSyncCmd = &cobra.Command{
 	Short: "Synchronize interfaces",
 	Long: `Synchronize interfaces in the realm with the given files.
 All given files will be parsed, and interfaces will be either updated or installed in the
-realm, depending on the realm's state.`,
+realm, depending on the realm's state.
+Interfaces to be updated are checked against the installed ones following the evolution rules
+of Astarte (see 'astartectl utils interfaces check-compat') before changing anything, unless
+--skip-compat-check is set.`,
 	Example: `  astartectl realm-management interfaces sync interfaces/*.json`,
 	Args:    cobra.MinimumNArgs(1),
 	RunE:    interfacesSyncF,
@@ -124,6 +133,8 @@ func init() {
 	RealmManagementCmd.AddCommand(interfacesCmd)
 
 	interfacesSyncCmd.PersistentFlags().BoolP("non-interactive", "y", false, "Non-interactive mode. Will answer yes by default to all questions.")
+	interfacesUpdateCmd.Flags().Bool("skip-compat-check", false, "Don't check that the interface file is a valid update of the installed interface.")
+	interfacesSyncCmd.Flags().Bool("skip-compat-check", false, "Don't check that interface files are valid updates of the installed interfaces.")
 
 	interfacesCmd.AddCommand(
 		interfacesListCmd,
@@ -144,8 +155,7 @@ func interfacesListF(command *cobra.Command, args []string) error {
 		os.Exit(1)
 	}
 
-	fmt.Println(realmInterfaces)
-	return nil
+	return printer.PrintList("Interface", realmInterfaces)
 }
 
 func interfacesVersionsF(command *cobra.Command, args []string) error {
@@ -157,8 +167,12 @@ func interfacesVersionsF(command *cobra.Command, args []string) error {
 		os.Exit(1)
 	}
 
-	fmt.Println(interfaceVersions)
-	return nil
+	t := printer.NewTable()
+	t.AppendHeader(table.Row{"Major Version"})
+	for _, v := range interfaceVersions {
+		t.AppendRow(table.Row{v})
+	}
+	return printer.PrintTable(t, interfaceVersions)
 }
 
 func interfacesShowF(command *cobra.Command, args []string) error {
@@ -174,13 +188,10 @@ func interfacesShowF(command *cobra.Command, args []string) error {
 		os.Exit(1)
 	}
 
-	respJSON, err := json.MarshalIndent(interfaceDefinition, "", "  ")
-	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
-	}
-	fmt.Println(string(respJSON))
-	return nil
+	return printer.Print(interfaceDefinition, func() {
+		respJSON, _ := json.MarshalIndent(interfaceDefinition, "", "  ")
+		fmt.Fprintln(printer.Out, string(respJSON))
+	})
 }
 
 func interfacesInstallF(command *cobra.Command, args []string) error {
@@ -195,8 +206,11 @@ func interfacesInstallF(command *cobra.Command, args []string) error {
 	}
 
 	if err = installInterface(realm, interfaceBody); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		if err := utils.PrintDryRun(err); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return nil
 	}
 
 	fmt.Println("ok")
@@ -214,6 +228,7 @@ func interfacesDeleteF(command *cobra.Command, args []string) error {
 	}
 
 	utils.MaybeCurlAndExit(deleteInterfaceCall, astarteAPIClient)
+	utils.MaybeDryRunAndExit(deleteInterfaceCall, astarteAPIClient)
 
 	deleteInterfaceRes, err := deleteInterfaceCall.Run(astarteAPIClient)
 	if err != nil {
@@ -238,9 +253,29 @@ func interfacesUpdateF(command *cobra.Command, args []string) error {
 		return err
 	}
 
+	skipCompatCheck, err := command.Flags().GetBool("skip-compat-check")
+	if err != nil {
+		return err
+	}
+	// There's nothing to check when only showing the request
+	if !skipCompatCheck && !viper.GetBool("realmmanagement-to-curl") {
+		interfaceDefinition, err := getInterfaceDefinition(realm, astarteInterface.Name, astarteInterface.MajorVersion)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if err := interfacediff.CheckCompatibility(interfaceDefinition, astarteInterface); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
 	if err := updateInterface(realm, astarteInterface.Name, astarteInterface.MajorVersion, astarteInterface); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		if err := utils.PrintDryRun(err); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return nil
 	}
 
 	fmt.Println("ok")
@@ -255,8 +290,14 @@ Install or update your interfaces one by one with 'interfaces install' or 'inter
 		os.Exit(1)
 	}
 
+	skipCompatCheck, err := command.Flags().GetBool("skip-compat-check")
+	if err != nil {
+		return err
+	}
+
 	interfacesToInstall := []interfaces.AstarteInterface{}
 	interfacesToUpdate := []interfaces.AstarteInterface{}
+	compatErrors := []error{}
 
 	for _, f := range args {
 		interfaceFile, err := os.ReadFile(f)
@@ -275,6 +316,11 @@ Install or update your interfaces one by one with 'interfaces install' or 'inter
 		} else {
 			if interfaceDefinition.MinorVersion < astarteInterface.MinorVersion {
 				interfacesToUpdate = append(interfacesToUpdate, astarteInterface)
+				if !skipCompatCheck {
+					if err := interfacediff.CheckCompatibility(interfaceDefinition, astarteInterface); err != nil {
+						compatErrors = append(compatErrors, err)
+					}
+				}
 			} else if interfaceDefinition.MinorVersion > astarteInterface.MinorVersion {
 				// Notify that the realm has a more recent revision
 				fmt.Fprintf(os.Stderr, "warn: Interface %s has version %d.%d in the realm and %d.%d in the local file", interfaceDefinition.Name,
@@ -283,6 +329,15 @@ Install or update your interfaces one by one with 'interfaces install' or 'inter
 		}
 	}
 
+	// Don't start syncing at all rather than failing midway
+	if len(compatErrors) > 0 {
+		for _, err := range compatErrors {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		fmt.Fprintln(os.Stderr, "\nNo interface was installed or updated. Use --skip-compat-check to skip this check.")
+		os.Exit(1)
+	}
+
 	if len(interfacesToInstall) == 0 && len(interfacesToUpdate) == 0 {
 		// All good in the hood
 		fmt.Println("Your realm is in sync with the provided interface files")
@@ -304,7 +359,7 @@ Install or update your interfaces one by one with 'interfaces install' or 'inter
 	if err != nil {
 		return err
 	}
-	if !y {
+	if !y && !utils.IsDryRun() {
 		if ok, err := utils.AskForConfirmation("Do you want to continue?"); !ok || err != nil {
 			return nil
 		}
@@ -313,14 +368,18 @@ Install or update your interfaces one by one with 'interfaces install' or 'inter
 	// Start syncing.
 	for _, v := range interfacesToInstall {
 		if err := installInterface(realm, v); err != nil {
-			fmt.Fprintf(os.Stderr, "Could not install interface %s: %s\n", v.Name, err)
+			if err := utils.PrintDryRun(err); err != nil {
+				fmt.Fprintf(os.Stderr, "Could not install interface %s: %s\n", v.Name, err)
+			}
 		} else {
 			fmt.Printf("Interface %s installed successfully\n", v.Name)
 		}
 	}
 	for _, v := range interfacesToUpdate {
 		if err := updateInterface(realm, v.Name, v.MajorVersion, v); err != nil {
-			fmt.Fprintf(os.Stderr, "Could not update interface %s: %s\n", v.Name, err)
+			if err := utils.PrintDryRun(err); err != nil {
+				fmt.Fprintf(os.Stderr, "Could not update interface %s: %s\n", v.Name, err)
+			}
 		} else {
 			fmt.Printf("Interface %s updated successfully to version %d.%d\n", v.Name, v.MajorVersion, v.MinorVersion)
 		}
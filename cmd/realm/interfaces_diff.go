@@ -0,0 +1,346 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package realm
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/astarte-platform/astarte-go/astarteservices"
+	"github.com/astarte-platform/astarte-go/client"
+	"github.com/astarte-platform/astarte-go/interfaces"
+	"github.com/astarte-platform/astartectl/config"
+	"github.com/astarte-platform/astartectl/interfacediff"
+	"github.com/astarte-platform/astartectl/printer"
+	"github.com/astarte-platform/astartectl/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var interfacesDiffCmd = &cobra.Command{
+	Use:   "diff <interface_files> [...]",
+	Short: "Show differences between interfaces",
+	Long: `Show what differs between two definitions of the same interface, mapping by mapping:
+added and removed endpoints, and changes to their type, reliability, retention, explicit_timestamp,
+description and any other field.
+
+By default, each given interface file is compared with the same major version of the interface
+installed in the realm.
+With --local, exactly two interface files are given, and the first one is compared with the second.
+With --against-context, the interfaces installed in the realm are compared with the ones installed
+in the realm of another context on the same cluster. Arguments are then the names of the interfaces
+to compare, or none to compare all interfaces of both realms.
+
+Like diff, the command exits with status 1 when any difference is found.
+This command does not support the --to-curl flag.`,
+	Example: `  astartectl realm-management interfaces diff interfaces/*.json
+  astartectl realm-management interfaces diff --local com.my.Interface.json new/com.my.Interface.json
+  astartectl realm-management interfaces diff --against-context production com.my.Interface`,
+	RunE: interfacesDiffF,
+}
+
+// interfaceDiff is the comparison of two definitions of the same major version of an interface,
+// read from the Old and New sources
+type interfaceDiff struct {
+	Name    string                 `json:"interface_name"`
+	Major   int                    `json:"version_major"`
+	Old     string                 `json:"old"`
+	New     string                 `json:"new"`
+	Status  string                 `json:"status"`
+	Changes []interfacediff.Change `json:"changes,omitempty"`
+}
+
+// interfaceKey identifies a major version of an interface
+type interfaceKey struct {
+	name  string
+	major int
+}
+
+func init() {
+	interfacesDiffCmd.Flags().Bool("local", false, "Compare two local interface files instead of using the realm")
+	interfacesDiffCmd.Flags().String("against-context", "", "Compare the realm with the realm of this context, on the same cluster")
+	_ = interfacesDiffCmd.RegisterFlagCompletionFunc("against-context", utils.CompleteContexts)
+
+	interfacesCmd.AddCommand(interfacesDiffCmd)
+}
+
+func interfacesDiffF(command *cobra.Command, args []string) error {
+	if viper.GetBool("realmmanagement-to-curl") {
+		fmt.Println(`'interfaces diff' does not support the --to-curl option. Use 'interfaces show' to get the content of an interface.`)
+		os.Exit(1)
+	}
+
+	local, err := command.Flags().GetBool("local")
+	if err != nil {
+		return err
+	}
+	againstContext, err := command.Flags().GetString("against-context")
+	if err != nil {
+		return err
+	}
+
+	var diffs []interfaceDiff
+	switch {
+	case local && againstContext != "":
+		return errors.New("--local and --against-context can't be used together")
+	case local:
+		if len(args) != 2 {
+			return errors.New("--local requires exactly two interface files")
+		}
+		diffs, err = diffInterfaceFiles(args[0], args[1])
+	case againstContext != "":
+		diffs, err = diffRealms(againstContext, args)
+	default:
+		if len(args) == 0 {
+			return errors.New("at least one interface file is required")
+		}
+		diffs, err = diffInterfaceFilesWithRealm(args)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if err := printer.Print(diffs, func() { printInterfaceDiffs(diffs) }); err != nil {
+		return err
+	}
+	for _, d := range diffs {
+		if d.Status != "unchanged" {
+			os.Exit(1)
+		}
+	}
+	return nil
+}
+
+func diffInterfaceFiles(oldFile, newFile string) ([]interfaceDiff, error) {
+	oldInterface, err := readInterfaceFile(oldFile)
+	if err != nil {
+		return nil, err
+	}
+	newInterface, err := readInterfaceFile(newFile)
+	if err != nil {
+		return nil, err
+	}
+	if oldInterface.Name != newInterface.Name || oldInterface.MajorVersion != newInterface.MajorVersion {
+		return nil, fmt.Errorf("%s and %s don't define the same interface major version: %s v%d and %s v%d", oldFile, newFile,
+			oldInterface.Name, oldInterface.MajorVersion, newInterface.Name, newInterface.MajorVersion)
+	}
+	return []interfaceDiff{newInterfaceDiff(oldFile, &oldInterface, newFile, &newInterface)}, nil
+}
+
+func diffInterfaceFilesWithRealm(files []string) ([]interfaceDiff, error) {
+	installed, err := listInterfaces(realm)
+	if err != nil {
+		return nil, err
+	}
+	installedNames := map[string]bool{}
+	for _, name := range installed {
+		installedNames[name] = true
+	}
+
+	source := fmt.Sprintf("realm %s", realm)
+	diffs := []interfaceDiff{}
+	for _, f := range files {
+		localInterface, err := readInterfaceFile(f)
+		if err != nil {
+			return nil, err
+		}
+
+		var installedInterface *interfaces.AstarteInterface
+		if installedNames[localInterface.Name] {
+			majors, err := interfaceVersions(localInterface.Name)
+			if err != nil {
+				return nil, err
+			}
+			for _, m := range majors {
+				if m != localInterface.MajorVersion {
+					continue
+				}
+				interfaceDefinition, err := getInterfaceDefinition(realm, localInterface.Name, localInterface.MajorVersion)
+				if err != nil {
+					return nil, err
+				}
+				installedInterface = &interfaceDefinition
+			}
+		}
+		diffs = append(diffs, newInterfaceDiff(source, installedInterface, f, &localInterface))
+	}
+	return diffs, nil
+}
+
+func diffRealms(contextName string, names []string) ([]interfaceDiff, error) {
+	context, err := config.LoadContextConfiguration(config.GetConfigDir(), contextName)
+	if err != nil {
+		return nil, err
+	}
+	if context.Realm.Name == "" {
+		return nil, fmt.Errorf("context %s has no realm", contextName)
+	}
+	token, err := utils.ContextToken(contextName, []astarteservices.AstarteService{astarteservices.RealmManagement})
+	if err != nil {
+		return nil, err
+	}
+	otherClient, err := utils.APIClientWithToken(map[astarteservices.AstarteService]string{
+		astarteservices.RealmManagement: "individual-urls.realm-management",
+	}, token)
+	if err != nil {
+		return nil, err
+	}
+
+	oldInterfaces, err := realmInterfaces(astarteAPIClient, realm, names)
+	if err != nil {
+		return nil, err
+	}
+	newInterfaces, err := realmInterfaces(otherClient, context.Realm.Name, names)
+	if err != nil {
+		return nil, fmt.Errorf("realm %s: %w", context.Realm.Name, err)
+	}
+
+	keys := []interfaceKey{}
+	for k := range oldInterfaces {
+		keys = append(keys, k)
+	}
+	for k := range newInterfaces {
+		if _, ok := oldInterfaces[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].name != keys[j].name {
+			return keys[i].name < keys[j].name
+		}
+		return keys[i].major < keys[j].major
+	})
+
+	oldSource, newSource := fmt.Sprintf("realm %s", realm), fmt.Sprintf("realm %s", context.Realm.Name)
+	diffs := []interfaceDiff{}
+	for _, k := range keys {
+		var oldInterface, newInterface *interfaces.AstarteInterface
+		if i, ok := oldInterfaces[k]; ok {
+			oldInterface = &i
+		}
+		if i, ok := newInterfaces[k]; ok {
+			newInterface = &i
+		}
+		diffs = append(diffs, newInterfaceDiff(oldSource, oldInterface, newSource, newInterface))
+	}
+	return diffs, nil
+}
+
+// realmInterfaces returns all major versions of the interfaces named names installed in realmName,
+// or of all its interfaces when names is empty. Names which aren't installed are skipped.
+func realmInterfaces(c *client.Client, realmName string, names []string) (map[interfaceKey]interfaces.AstarteInterface, error) {
+	rawInstalled, err := parseRequest(c)(c.ListInterfaces(realmName))
+	if err != nil {
+		return nil, err
+	}
+	installed, _ := rawInstalled.([]string)
+	if len(names) > 0 {
+		requested := map[string]bool{}
+		for _, name := range names {
+			requested[name] = true
+		}
+		filtered := []string{}
+		for _, name := range installed {
+			if requested[name] {
+				filtered = append(filtered, name)
+			}
+		}
+		installed = filtered
+	}
+
+	ret := map[interfaceKey]interfaces.AstarteInterface{}
+	for _, name := range installed {
+		rawMajors, err := parseRequest(c)(c.ListInterfaceMajorVersions(realmName, name))
+		if err != nil {
+			return nil, err
+		}
+		majors, _ := rawMajors.([]int)
+		for _, major := range majors {
+			rawInterface, err := parseRequest(c)(c.GetInterface(realmName, name, major))
+			if err != nil {
+				return nil, err
+			}
+			ret[interfaceKey{name: name, major: major}], _ = rawInterface.(interfaces.AstarteInterface)
+		}
+	}
+	return ret, nil
+}
+
+func parseRequest(c *client.Client) func(client.AstarteRequest, error) (any, error) {
+	return func(req client.AstarteRequest, err error) (any, error) {
+		if err != nil {
+			return nil, err
+		}
+		res, err := req.Run(c)
+		if err != nil {
+			return nil, err
+		}
+		return res.Parse()
+	}
+}
+
+func readInterfaceFile(fileName string) (interfaces.AstarteInterface, error) {
+	contents, err := os.ReadFile(fileName)
+	if err != nil {
+		return interfaces.AstarteInterface{}, err
+	}
+	astarteInterface, err := interfaces.ParseInterface(contents)
+	if err != nil {
+		return interfaces.AstarteInterface{}, fmt.Errorf("%s: %w", fileName, err)
+	}
+	return astarteInterface, nil
+}
+
+// newInterfaceDiff compares two definitions of an interface, either of which can be missing
+func newInterfaceDiff(oldSource string, oldInterface *interfaces.AstarteInterface, newSource string, newInterface *interfaces.AstarteInterface) interfaceDiff {
+	d := interfaceDiff{Old: oldSource, New: newSource}
+	switch {
+	case oldInterface == nil:
+		d.Name, d.Major, d.Status = newInterface.Name, newInterface.MajorVersion, string(interfacediff.KindAdded)
+	case newInterface == nil:
+		d.Name, d.Major, d.Status = oldInterface.Name, oldInterface.MajorVersion, string(interfacediff.KindRemoved)
+	default:
+		d.Name, d.Major = newInterface.Name, newInterface.MajorVersion
+		d.Changes = interfacediff.Compare(*oldInterface, *newInterface)
+		d.Status = string(interfacediff.KindChanged)
+		if len(d.Changes) == 0 {
+			d.Status = "unchanged"
+		}
+	}
+	return d
+}
+
+func printInterfaceDiffs(diffs []interfaceDiff) {
+	for i, d := range diffs {
+		if i > 0 {
+			fmt.Fprintln(printer.Out)
+		}
+		fmt.Fprintf(printer.Out, "%s v%d (%s -> %s)\n", d.Name, d.Major, d.Old, d.New)
+		switch d.Status {
+		case string(interfacediff.KindAdded):
+			fmt.Fprintf(printer.Out, "  only in %s\n", d.New)
+		case string(interfacediff.KindRemoved):
+			fmt.Fprintf(printer.Out, "  only in %s\n", d.Old)
+		case "unchanged":
+			fmt.Fprintln(printer.Out, "  no differences")
+		}
+		for _, c := range d.Changes {
+			fmt.Fprintf(printer.Out, "  %s\n", c)
+		}
+	}
+}
@@ -17,6 +17,7 @@ package realm
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/astarte-platform/astartectl/printer"
 	"github.com/astarte-platform/astartectl/utils"
 	"github.com/spf13/cobra"
 	"os"
@@ -80,16 +81,16 @@ func init() {
 
 func triggersPoliciesListtF(command *cobra.Command, args []string) error {
 	realmPolicies, _ := listPolicies(realm)
-	fmt.Println(realmPolicies)
-	return nil
+	return printer.PrintList("Trigger Policy", realmPolicies)
 }
 
 func triggersPoliciesShowF(command *cobra.Command, args []string) error {
 	policyName := args[0]
 	policyDefinition, _ := getPolicyDefinition(realm, policyName)
-	respJSON, _ := json.MarshalIndent(policyDefinition, "", "  ")
-	fmt.Println(string(respJSON))
-	return nil
+	return printer.Print(policyDefinition, func() {
+		respJSON, _ := json.MarshalIndent(policyDefinition, "", "  ")
+		fmt.Fprintln(printer.Out, string(respJSON))
+	})
 }
 
 func triggersPoliciesInstallF(command *cobra.Command, args []string) error {
@@ -123,6 +124,7 @@ func triggersPoliciesDeleteF(command *cobra.Command, args []string) error {
 	}
 
 	utils.MaybeCurlAndExit(deleteTriggerPolicyCall, astarteAPIClient)
+	utils.MaybeDryRunAndExit(deleteTriggerPolicyCall, astarteAPIClient)
 
 	deleteTriggerPolicyRes, err := deleteTriggerPolicyCall.Run(astarteAPIClient)
 	if err != nil {
@@ -143,6 +145,7 @@ func installTriggerPolicy(realm string, policy any) error {
 	}
 
 	utils.MaybeCurlAndExit(installTriggerDeliveryCall, astarteAPIClient)
+	utils.MaybeDryRunAndExit(installTriggerDeliveryCall, astarteAPIClient)
 
 	installTriggerDeliveryRes, err := installTriggerDeliveryCall.Run(astarteAPIClient)
 	if err != nil {
@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/astarte-platform/astarte-go/triggers"
+	"github.com/astarte-platform/astartectl/printer"
 	"github.com/astarte-platform/astartectl/utils"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -110,16 +111,16 @@ func init() {
 
 func triggersListF(command *cobra.Command, args []string) error {
 	realmTriggers, _ := listTriggers(realm)
-	fmt.Println(realmTriggers)
-	return nil
+	return printer.PrintList("Trigger", realmTriggers)
 }
 
 func triggersShowF(command *cobra.Command, args []string) error {
 	triggerName := args[0]
 	triggerDefinition, _ := getTriggerDefinition(realm, triggerName)
-	respJSON, _ := json.MarshalIndent(triggerDefinition, "", "  ")
-	fmt.Println(string(respJSON))
-	return nil
+	return printer.Print(triggerDefinition, func() {
+		respJSON, _ := json.MarshalIndent(triggerDefinition, "", "  ")
+		fmt.Fprintln(printer.Out, string(respJSON))
+	})
 }
 
 func triggersInstallF(command *cobra.Command, args []string) error {
@@ -134,7 +135,13 @@ func triggersInstallF(command *cobra.Command, args []string) error {
 		return err
 	}
 
-	_ = installTrigger(realm, triggerBody)
+	if err := installTrigger(realm, triggerBody); err != nil {
+		if err := utils.PrintDryRun(err); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return nil
+	}
 
 	fmt.Println("ok")
 	return nil
@@ -149,6 +156,7 @@ func triggersDeleteF(command *cobra.Command, args []string) error {
 	}
 
 	utils.MaybeCurlAndExit(deleteTriggerCall, astarteAPIClient)
+	utils.MaybeDryRunAndExit(deleteTriggerCall, astarteAPIClient)
 
 	deleteTriggerRes, err := deleteTriggerCall.Run(astarteAPIClient)
 	if err != nil {
@@ -278,14 +286,18 @@ func triggersSyncF(command *cobra.Command, args []string) error {
 
 		fmt.Printf("The following new triggers will be installed: %+q \n", list)
 
-		if ok, err := utils.AskForConfirmation("Do you want to continue?"); !ok || err != nil {
-			fmt.Printf("aborting")
-			return nil
+		if !utils.IsDryRun() {
+			if ok, err := utils.AskForConfirmation("Do you want to continue?"); !ok || err != nil {
+				fmt.Printf("aborting")
+				return nil
+			}
 		}
 
 		for _, trigger := range triggersToInstall {
 			if err := installTrigger(realm, trigger); err != nil {
-				fmt.Fprintf(os.Stderr, "Could not install trigger %s: %s\n", trigger.Name, err)
+				if err := utils.PrintDryRun(err); err != nil {
+					fmt.Fprintf(os.Stderr, "Could not install trigger %s: %s\n", trigger.Name, err)
+				}
 			} else {
 				fmt.Printf("trigger %s installed successfully\n", trigger.Name)
 			}
@@ -299,13 +311,17 @@ func triggersSyncF(command *cobra.Command, args []string) error {
 		}
 		if y {
 			fmt.Printf("The following triggers already exists and WILL be DELETED and RECREATED: %+q \n", listExisting)
-			if ok, err := utils.AskForConfirmation("Do you want to continue?"); !ok || err != nil {
-				fmt.Printf("aborting")
-				return nil
+			if !utils.IsDryRun() {
+				if ok, err := utils.AskForConfirmation("Do you want to continue?"); !ok || err != nil {
+					fmt.Printf("aborting")
+					return nil
+				}
 			}
 			for _, trigger := range triggersToUpdate {
 				if err := updateTrigger(realm, trigger.Name, trigger); err != nil {
-					fmt.Fprintf(os.Stderr, "Could not update trigger %s: %s\n", trigger.Name, err)
+					if err := utils.PrintDryRun(err); err != nil {
+						fmt.Fprintf(os.Stderr, "Could not update trigger %s: %s\n", trigger.Name, err)
+					}
 				} else {
 					fmt.Printf("trigger %s updated successfully\n", trigger.Name)
 				}
@@ -344,9 +360,11 @@ func updateTrigger(realm string, triggername string, newtrig triggers.AstarteTri
 	}
 	utils.MaybeCurlAndExit(deleteTriggercall, astarteAPIClient)
 
-	_, err = deleteTriggercall.Run(astarteAPIClient)
-	if err != nil {
-		return err
+	if _, err = deleteTriggercall.Run(astarteAPIClient); err != nil {
+		// On --dry-run, go on describing the installation of the new trigger too
+		if err := utils.PrintDryRun(err); err != nil {
+			return err
+		}
 	}
 
 	updateTriggerCall, err := astarteAPIClient.InstallTrigger(realm, newtrig)
@@ -18,15 +18,19 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/astarte-platform/astartectl/cmd/appengine"
+	"github.com/astarte-platform/astartectl/cmd/apply"
 	"github.com/astarte-platform/astartectl/cmd/cluster"
 	configcmd "github.com/astarte-platform/astartectl/cmd/config"
+	"github.com/astarte-platform/astartectl/cmd/dev"
 	"github.com/astarte-platform/astartectl/cmd/housekeeping"
 	"github.com/astarte-platform/astartectl/cmd/pairing"
 	"github.com/astarte-platform/astartectl/cmd/realm"
 	"github.com/astarte-platform/astartectl/cmd/utils"
 	"github.com/astarte-platform/astartectl/config"
+	"github.com/astarte-platform/astartectl/printer"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -49,6 +53,12 @@ See below for usage details`,
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
+	if maybeFanOut(os.Args[1:]) {
+		return
+	}
+	if maybeRunPlugin(os.Args[1:]) {
+		return
+	}
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
@@ -62,15 +72,37 @@ func init() {
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 	rootCmd.PersistentFlags().String("config-dir", "", fmt.Sprintf("config directory (default is %s)", config.GetDefaultConfigDir()))
+	rootCmd.PersistentFlags().String("config-file", "", fmt.Sprintf("Configuration files to use instead of the config directory, in the format of config export, separated by '%c'. Can be set through ASTARTECTL_CONFIG.", os.PathListSeparator))
 	rootCmd.PersistentFlags().StringVar(&cfgContext, "context", "", "Configuration context to use. When not specified, defaults to current context.")
+	rootCmd.PersistentFlags().StringSlice("contexts", nil, "Run the command once for each of these contexts. Accepts glob patterns (e.g. customer-*).")
+	rootCmd.PersistentFlags().Bool("all-contexts", false, "Run the command once for each context.")
+	rootCmd.PersistentFlags().Int("parallelism", 4, "Maximum number of contexts the command runs for at the same time, with --contexts or --all-contexts.")
 	rootCmd.PersistentFlags().StringP("astarte-url", "u", "", "Base url for your Astarte deployment (e.g. https://api.astarte.example.com)")
 	rootCmd.PersistentFlags().StringP("token", "t", "", "Token for authenticating against Astarte APIs. When set, it takes precedence over any private key setting. Claims in the token have to match the permissions needed for the individual command.")
 	rootCmd.PersistentFlags().Bool("ignore-ssl-errors", false, "When set, ignore SSL errors towards the Astarte APIs.")
+	rootCmd.PersistentFlags().String("ca-file", "", "Path to a PEM bundle of CAs to trust, in addition to the system ones, when connecting to the Astarte APIs.")
+	rootCmd.PersistentFlags().String("client-cert", "", "Path to a PEM client certificate presented to the Astarte APIs. Requires --client-key.")
+	rootCmd.PersistentFlags().String("client-key", "", "Path to the PEM private key of the client certificate.")
+	rootCmd.PersistentFlags().Bool("token-cache", true, "When set, tokens generated from private keys are cached on disk and reused until they expire.")
+	rootCmd.PersistentFlags().Int("retry-max-attempts", 3, "Maximum number of attempts for API requests failing with a transient error (e.g. 429, 502, 503). 1 disables retries. Each attempt times out after 30s, so a request takes at most this many times 30s, plus the waits between attempts.")
+	rootCmd.PersistentFlags().Duration("retry-backoff", 500*time.Millisecond, "Time to wait before retrying a failed API request. It doubles at each retry.")
+	rootCmd.PersistentFlags().Duration("retry-max-backoff", 10*time.Second, "Maximum time to wait between two attempts of an API request, including the time requested through Retry-After. It adds up to the 30s timeout of each attempt.")
+	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "When set, log method, URL, status and latency of every API request to stderr.")
+	rootCmd.PersistentFlags().Bool("trace", false, "When set, log every API request and response to stderr, including headers and bodies. Secrets are redacted.")
+	rootCmd.PersistentFlags().Bool("dry-run", false, "When set, print the requests which would change the state of Astarte instead of sending them.")
+	rootCmd.PersistentFlags().String("audit-log", "", "Path of the audit log recording every request changing the state of Astarte (default is audit.log in the config directory)")
+	rootCmd.PersistentFlags().StringP("output", "o", "table", fmt.Sprintf("Output format. One of: %s", strings.Join(printer.SupportedFormats, ", ")))
+
+	registerFlagCompletions()
 
 	if err := viper.BindPFlag("config-dir", rootCmd.PersistentFlags().Lookup("config-dir")); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
+	if err := viper.BindPFlag("config-file", rootCmd.PersistentFlags().Lookup("config-file")); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 	if err := viper.BindPFlag("url", rootCmd.PersistentFlags().Lookup("astarte-url")); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
@@ -83,6 +115,54 @@ func init() {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
+	if err := viper.BindPFlag("tls.ca-file", rootCmd.PersistentFlags().Lookup("ca-file")); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := viper.BindPFlag("tls.client-cert", rootCmd.PersistentFlags().Lookup("client-cert")); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := viper.BindPFlag("tls.client-key", rootCmd.PersistentFlags().Lookup("client-key")); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := viper.BindPFlag("token-cache", rootCmd.PersistentFlags().Lookup("token-cache")); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := viper.BindPFlag("retry.max-attempts", rootCmd.PersistentFlags().Lookup("retry-max-attempts")); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := viper.BindPFlag("retry.backoff", rootCmd.PersistentFlags().Lookup("retry-backoff")); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := viper.BindPFlag("retry.max-backoff", rootCmd.PersistentFlags().Lookup("retry-max-backoff")); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose")); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := viper.BindPFlag("trace", rootCmd.PersistentFlags().Lookup("trace")); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := viper.BindPFlag("output", rootCmd.PersistentFlags().Lookup("output")); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := viper.BindPFlag("dry-run", rootCmd.PersistentFlags().Lookup("dry-run")); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := viper.BindPFlag("audit-log", rootCmd.PersistentFlags().Lookup("audit-log")); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 
 	rootCmd.AddCommand(housekeeping.HousekeepingCmd)
 	rootCmd.AddCommand(pairing.PairingCmd)
@@ -91,6 +171,8 @@ func init() {
 	rootCmd.AddCommand(appengine.AppEngineCmd)
 	rootCmd.AddCommand(cluster.ClusterCmd)
 	rootCmd.AddCommand(configcmd.ConfigCmd)
+	rootCmd.AddCommand(apply.ApplyCmd)
+	rootCmd.AddCommand(dev.DevCmd)
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -19,6 +19,7 @@ import (
 	"os"
 
 	"github.com/astarte-platform/astarte-go/interfaces"
+	"github.com/astarte-platform/astartectl/interfacediff"
 
 	"github.com/spf13/cobra"
 )
@@ -41,11 +42,25 @@ Returns 0 and does not print anything if the interface is valid, returns 1 and p
 	RunE:    validateInterfaceF,
 }
 
+var checkCompatInterfaceCmd = &cobra.Command{
+	Use:   "check-compat <old_interface_file> <new_interface_file>",
+	Short: "Checks whether an interface can be updated to a new definition",
+	Long: `Checks whether <new_interface_file> is a valid update of <old_interface_file> within the same major version,
+following the evolution rules of Astarte: the minor version must increase, type, ownership and aggregation can't change,
+and mappings can be added but not removed or retyped. Any other change requires a new major version.
+
+Returns 0 and does not print anything if the update is valid, returns 1 and prints the rules it breaks if it isn't.`,
+	Example: `  astartectl utils interfaces check-compat com.my.Interface.json new/com.my.Interface.json`,
+	Args:    cobra.ExactArgs(2),
+	RunE:    checkCompatInterfaceF,
+}
+
 func init() {
 	UtilsCmd.AddCommand(interfacesCmd)
 
 	interfacesCmd.AddCommand(
 		validateInterfaceCmd,
+		checkCompatInterfaceCmd,
 	)
 }
 
@@ -59,3 +74,23 @@ func validateInterfaceF(command *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func checkCompatInterfaceF(command *cobra.Command, args []string) error {
+	oldInterface, err := interfaces.ParseInterfaceFrom(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s is not a valid Astarte Interface: %s\n", args[0], err)
+		os.Exit(1)
+	}
+	newInterface, err := interfaces.ParseInterfaceFrom(args[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s is not a valid Astarte Interface: %s\n", args[1], err)
+		os.Exit(1)
+	}
+
+	if err := interfacediff.CheckCompatibility(oldInterface, newInterface); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	return nil
+}
@@ -26,7 +26,6 @@ import (
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/x509"
-	"encoding/base64"
 	"encoding/json"
 	"encoding/pem"
 	"errors"
@@ -258,7 +257,7 @@ func genJwtF(command *cobra.Command, args []string) error {
 			loadedKey = cluster.Housekeeping.Key
 		}
 
-		decoded, err := base64.StdEncoding.DecodeString(loadedKey)
+		decoded, err := config.DecodeKey(loadedKey)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
@@ -281,7 +280,7 @@ func genJwtF(command *cobra.Command, args []string) error {
 }
 
 func savePEMKey(fileName string, key *ecdsa.PrivateKey) {
-	outFile, err := os.Create(fileName)
+	outFile, err := os.OpenFile(fileName, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
 	checkError(err)
 	defer outFile.Close()
 
@@ -0,0 +1,110 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const auditLogName = "audit.log"
+
+// AuditEntry is a line of the audit log, recording a request which changed, or tried to change,
+// the state of Astarte
+type AuditEntry struct {
+	Time    time.Time `json:"time"`
+	User    string    `json:"user"`
+	Context string    `json:"context,omitempty"`
+	Realm   string    `json:"realm,omitempty"`
+	Command string    `json:"command"`
+	Method  string    `json:"method"`
+	URL     string    `json:"url"`
+	// Resource is the path of the target of the request, relative to the realm for realm APIs
+	Resource string `json:"resource"`
+	// Status is the HTTP status of the response, missing when no response was received
+	Status int    `json:"status,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Succeeded returns true if the request was completed successfully
+func (e AuditEntry) Succeeded() bool {
+	return e.Error == "" && e.Status >= 200 && e.Status < 300
+}
+
+// GetAuditLogPath returns the path of the audit log. It is audit.log in the config directory,
+// unless another path is set through audit-log in the base configuration or --audit-log
+func GetAuditLogPath() string {
+	if auditLog := viper.GetString("audit-log"); auditLog != "" {
+		return auditLog
+	}
+	return path.Join(GetConfigDir(), auditLogName)
+}
+
+// AppendAuditEntry appends entry to the audit log in fileName, creating it if needed
+func AppendAuditEntry(fileName string, entry AuditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(fileName), 0700); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(fileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	// A single write, so that entries of concurrent invocations don't get mixed
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ReadAuditLog returns all entries of the audit log in fileName, oldest first. A missing audit
+// log is an empty one.
+func ReadAuditLog(fileName string) ([]AuditEntry, error) {
+	f, err := os.Open(fileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return []AuditEntry{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	entries := []AuditEntry{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		entry := AuditEntry{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", fileName, lineNumber, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
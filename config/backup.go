@@ -0,0 +1,45 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// BackupContextConfiguration saves a copy of a context configuration in the backups directory of the
+// config directory, and returns its path. Keys are copied as they are stored, so they stay encrypted
+// when the configuration encrypts keys.
+func BackupContextConfiguration(configDir, contextName string) (string, error) {
+	context, err := LoadContextConfiguration(configDir, contextName)
+	if err != nil {
+		return "", err
+	}
+	contents, err := yaml.Marshal(context)
+	if err != nil {
+		return "", err
+	}
+
+	backupsDir := path.Join(configDir, "backups")
+	if err := os.MkdirAll(backupsDir, 0700); err != nil {
+		return "", err
+	}
+	backupPath := path.Join(backupsDir, fmt.Sprintf("%s-%s.yaml", contextName, time.Now().UTC().Format("20060102T150405Z")))
+	return backupPath, writeFileAtomically(backupPath, contents, secretFileMode)
+}
@@ -16,7 +16,6 @@ package config
 
 import (
 	"gopkg.in/yaml.v2"
-	"os"
 	"path"
 )
 
@@ -26,6 +25,10 @@ const baseConfigName = "astartectl"
 type BaseConfigFile struct {
 	// CurrentContext represents the context which should be used when no context is explicitly specified
 	CurrentContext string `yaml:"context" json:"context"`
+	// KeyCheck is a known value encrypted with the passphrase of the keys. When set, keys are stored encrypted
+	KeyCheck string `yaml:"key-check,omitempty" json:"key-check,omitempty"`
+	// AuditLog is the path of the audit log, when not in the config directory
+	AuditLog string `yaml:"audit-log,omitempty" json:"audit-log,omitempty"`
 }
 
 // LoadBaseConfiguration loads the base configuration from a config directory
@@ -34,6 +37,11 @@ func LoadBaseConfiguration(configDir string) (BaseConfigFile, error) {
 		configDir = GetDefaultConfigDir()
 	}
 
+	if files := GetConfigFiles(); len(files) > 0 {
+		bundle, err := LoadConfigFiles(files)
+		return bundle.BaseConfig, err
+	}
+
 	config := BaseConfigFile{}
 	contents, err := loadYamlFile(configDir, baseConfigName)
 	if err != nil {
@@ -45,10 +53,29 @@ func LoadBaseConfiguration(configDir string) (BaseConfigFile, error) {
 
 // SaveBaseConfiguration saves the base configuration in a config directory
 func SaveBaseConfiguration(configDir string, configuration BaseConfigFile) error {
+	return saveBaseConfiguration(nil, configDir, configuration)
+}
+
+// saveBaseConfiguration is SaveBaseConfiguration, called while holding held, if not nil
+func saveBaseConfiguration(held *configLock, configDir string, configuration BaseConfigFile) error {
 	if configDir == "" {
 		configDir = GetDefaultConfigDir()
 	}
 
+	lock, unlock, err := lockConfigDir(held, configDir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if files := GetConfigFiles(); len(files) > 0 {
+		// Like kubectl, the base configuration is saved to the first file
+		return updateConfigFile(lock, files[0], func(bundle *Bundle) error {
+			bundle.BaseConfig = configuration
+			return nil
+		})
+	}
+
 	if err := ensureConfigDirectoryStructure(configDir); err != nil {
 		return err
 	}
@@ -58,5 +85,5 @@ func SaveBaseConfiguration(configDir string, configuration BaseConfigFile) error
 		return err
 	}
 
-	return os.WriteFile(path.Join(configDir, baseConfigName+".yaml"), contents, 0644)
+	return writeFileAtomically(path.Join(configDir, baseConfigName+".yaml"), contents, configFileMode)
 }
@@ -14,6 +14,23 @@
 
 package config
 
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"golang.org/x/term"
+)
+
+// bundlePassphraseEnv is the environment variable holding the passphrase of encrypted bundles
+const bundlePassphraseEnv = "ASTARTECTL_BUNDLE_PASSPHRASE"
+
 // Bundle represents a bundle encapsulating all configuration files
 type Bundle struct {
 	// BaseConfig is the base config for astartectl
@@ -83,39 +100,186 @@ func CreateBundleFromDirectory(configDir string, clusters, contexts []string) (B
 	return bundle, nil
 }
 
-// LoadBundleToDirectory loads a bundle into a config directory, overwriting matching files, overwriting any
-// overlapping entry. When failing, it might have partially loaded some of the entries in the file.
+// LoadBundleToDirectory loads a bundle into a config directory. Overlapping entries are overwritten when
+// overwrite is true, and left untouched otherwise. Either all the entries are loaded, or none is.
 // Clusters and Contexts, when specified, are a list of clusters and contexts to load.
 // An empty list means "all"
 func LoadBundleToDirectory(bundle Bundle, configDir string, clusters, contexts []string, overwrite bool) error {
-	// Load clusters first
-	for name, configuration := range bundle.Clusters {
-		if len(clusters) > 0 {
-			if !existsInStringSlice(name, clusters) {
-				// skip
-				continue
-			}
+	strategy := KeepLocalStrategy
+	if overwrite {
+		strategy = PreferImportStrategy
+	}
+	plan, err := PlanBundleImport(bundle, configDir, clusters, contexts, strategy)
+	if err != nil {
+		return err
+	}
+	return ApplyImportPlan(plan, configDir)
+}
+
+// RedactBundleSecrets returns a copy of bundle without private keys and tokens, so that it can be
+// shared. Clusters and contexts keep all other settings, including credential commands.
+func RedactBundleSecrets(bundle Bundle) Bundle {
+	redacted := Bundle{
+		BaseConfig: bundle.BaseConfig,
+		Clusters:   map[string]ClusterFile{},
+		Contexts:   map[string]ContextFile{},
+	}
+	// Without keys, there is nothing the key check could be used for
+	redacted.BaseConfig.KeyCheck = ""
+
+	for name, cluster := range bundle.Clusters {
+		cluster.Housekeeping.Key = ""
+		cluster.Housekeeping.Token = ""
+		redacted.Clusters[name] = cluster
+	}
+	for name, context := range bundle.Contexts {
+		context.Realm.Key = ""
+		context.Realm.Token = ""
+		redacted.Contexts[name] = context
+	}
+	return redacted
+}
+
+// EncryptBundle returns bundle as JSON encrypted for recipients, in the armored age format.
+// Use age.NewScryptRecipient to encrypt it with a passphrase.
+func EncryptBundle(bundle Bundle, recipients ...age.Recipient) ([]byte, error) {
+	jsonBytes, err := json.Marshal(bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted := &bytes.Buffer{}
+	armorWriter := armor.NewWriter(encrypted)
+	w, err := age.Encrypt(armorWriter, recipients...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(jsonBytes); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	if err := armorWriter.Close(); err != nil {
+		return nil, err
+	}
+	return encrypted.Bytes(), nil
+}
+
+// IsEncryptedBundle returns true if contents is a bundle encrypted with age, armored or not
+func IsEncryptedBundle(contents []byte) bool {
+	trimmed := bytes.TrimSpace(contents)
+	return bytes.HasPrefix(trimmed, []byte(armor.Header)) || bytes.HasPrefix(trimmed, []byte("age-encryption.org/"))
+}
+
+// ParseBundle parses a bundle exported by astartectl. Encrypted bundles are decrypted with identities,
+// or with a passphrase when no identity is given.
+func ParseBundle(contents []byte, identities ...age.Identity) (Bundle, error) {
+	bundle := Bundle{}
+	if !IsEncryptedBundle(contents) {
+		err := json.Unmarshal(contents, &bundle)
+		return bundle, err
+	}
+
+	if len(identities) == 0 {
+		passphrase, err := BundlePassphrase(false)
+		if err != nil {
+			return bundle, err
 		}
-		if err := SaveClusterConfiguration(configDir, name, configuration, overwrite); err != nil {
-			return err
+		identity, err := age.NewScryptIdentity(passphrase)
+		if err != nil {
+			return bundle, err
 		}
+		identities = []age.Identity{identity}
 	}
 
-	// Then contexts
-	for name, configuration := range bundle.Contexts {
-		if len(contexts) > 0 {
-			if !existsInStringSlice(name, contexts) {
-				// skip
-				continue
+	var encrypted io.Reader = bytes.NewReader(contents)
+	if bytes.HasPrefix(bytes.TrimSpace(contents), []byte(armor.Header)) {
+		encrypted = armor.NewReader(bytes.NewReader(bytes.TrimSpace(contents)))
+	}
+	r, err := age.Decrypt(encrypted, identities...)
+	if err != nil {
+		return bundle, fmt.Errorf("could not decrypt the bundle: %w", err)
+	}
+	decrypted, err := io.ReadAll(r)
+	if err != nil {
+		return bundle, fmt.Errorf("could not decrypt the bundle: %w", err)
+	}
+	err = json.Unmarshal(decrypted, &bundle)
+	return bundle, err
+}
+
+// BundlePassphrase returns the passphrase of an encrypted bundle, reading it from the ASTARTECTL_BUNDLE_PASSPHRASE
+// environment variable or prompting the user. When confirm is true, the user is asked to type it twice.
+func BundlePassphrase(confirm bool) (string, error) {
+	if passphrase, ok := os.LookupEnv(bundlePassphraseEnv); ok && passphrase != "" {
+		return passphrase, nil
+	}
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("a passphrase is required for encrypted bundles, set it through %s", bundlePassphraseEnv)
+	}
+	passphrase, err := readPassphrase("Passphrase for the bundle: ")
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", errors.New("passphrase can't be empty")
+	}
+	if confirm {
+		confirmation, err := readPassphrase("Confirm passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		if confirmation != passphrase {
+			return "", errors.New("passphrases don't match")
+		}
+	}
+	return passphrase, nil
+}
+
+// ParseAgeRecipients parses age recipients, either public keys (age1...) or paths of files listing them
+func ParseAgeRecipients(values []string) ([]age.Recipient, error) {
+	recipients := []age.Recipient{}
+	for _, v := range values {
+		if strings.HasPrefix(v, "age1") {
+			recipient, err := age.ParseX25519Recipient(v)
+			if err != nil {
+				return nil, err
 			}
+			recipients = append(recipients, recipient)
+			continue
 		}
-		if err := SaveContextConfiguration(configDir, name, configuration, overwrite); err != nil {
-			return err
+		f, err := os.Open(v)
+		if err != nil {
+			return nil, err
 		}
+		fileRecipients, err := age.ParseRecipients(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", v, err)
+		}
+		recipients = append(recipients, fileRecipients...)
 	}
+	return recipients, nil
+}
 
-	// Then main conf
-	return SaveBaseConfiguration(configDir, bundle.BaseConfig)
+// ParseAgeIdentities parses the age identities in the files fileNames
+func ParseAgeIdentities(fileNames []string) ([]age.Identity, error) {
+	identities := []age.Identity{}
+	for _, fileName := range fileNames {
+		f, err := os.Open(fileName)
+		if err != nil {
+			return nil, err
+		}
+		fileIdentities, err := age.ParseIdentities(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", fileName, err)
+		}
+		identities = append(identities, fileIdentities...)
+	}
+	return identities, nil
 }
 
 func existsInStringSlice(match string, list []string) bool {
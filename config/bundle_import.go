@@ -0,0 +1,394 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ImportStrategy decides how the entries of a bundle conflicting with local ones are imported
+type ImportStrategy string
+
+const (
+	// KeepLocalStrategy leaves conflicting local entries untouched
+	KeepLocalStrategy ImportStrategy = "keep-local"
+	// PreferImportStrategy overwrites conflicting local entries with the imported ones
+	PreferImportStrategy ImportStrategy = "prefer-import"
+	// RenameConflictsStrategy imports conflicting entries under a new name
+	RenameConflictsStrategy ImportStrategy = "rename-conflicts"
+)
+
+// ImportStrategies lists the available import strategies
+var ImportStrategies = []string{string(KeepLocalStrategy), string(PreferImportStrategy), string(RenameConflictsStrategy)}
+
+// ParseImportStrategy returns the ImportStrategy named strategy
+func ParseImportStrategy(strategy string) (ImportStrategy, error) {
+	for _, s := range ImportStrategies {
+		if s == strategy {
+			return ImportStrategy(s), nil
+		}
+	}
+	return "", fmt.Errorf("invalid import strategy %q, must be one of: %s", strategy, strings.Join(ImportStrategies, ", "))
+}
+
+// ImportAction is what importing a bundle does to one of its entries
+type ImportAction string
+
+const (
+	// ImportAdd adds an entry which does not exist locally
+	ImportAdd ImportAction = "add"
+	// ImportChange overwrites a local entry
+	ImportChange ImportAction = "change"
+	// ImportUnchanged is an entry identical to the local one
+	ImportUnchanged ImportAction = "unchanged"
+	// ImportKeepLocal skips an entry conflicting with the local one
+	ImportKeepLocal ImportAction = "keep-local"
+	// ImportRename adds an entry conflicting with the local one under a new name
+	ImportRename ImportAction = "rename"
+)
+
+// FieldChange is a field of an entry which differs between the local and the imported configuration
+type FieldChange struct {
+	Field    string `json:"field"`
+	Local    string `json:"local"`
+	Imported string `json:"imported"`
+}
+
+// ImportPlanEntry describes how an entry of a bundle is imported
+type ImportPlanEntry struct {
+	// Kind is either cluster, context or config, for the base configuration
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// TargetName is the name the entry is imported as. It differs from Name only when renamed
+	TargetName string       `json:"target_name"`
+	Action     ImportAction `json:"action"`
+	// Changes are the differences from the local entry, for changed and conflicting entries
+	Changes []FieldChange `json:"changes,omitempty"`
+
+	contents interface{}
+}
+
+// ImportPlan describes how a bundle is imported in a config directory
+type ImportPlan struct {
+	Entries []ImportPlanEntry `json:"entries"`
+}
+
+// PlanBundleImport returns how bundle would be imported into configDir with strategy, without changing it.
+// Clusters and Contexts, when specified, are a list of clusters and contexts to load.
+// An empty list means "all"
+func PlanBundleImport(bundle Bundle, configDir string, clusters, contexts []string, strategy ImportStrategy) (ImportPlan, error) {
+	plan := ImportPlan{Entries: []ImportPlanEntry{}}
+
+	localClusters, err := ListClusterConfigurations(configDir)
+	if err != nil {
+		return plan, err
+	}
+	takenClusterNames := append([]string{}, localClusters...)
+	clusterRenames := map[string]string{}
+	for _, name := range sortedKeys(bundle.Clusters) {
+		if len(clusters) > 0 && !existsInStringSlice(name, clusters) {
+			continue
+		}
+		entry := ImportPlanEntry{Kind: "cluster", Name: name, TargetName: name, contents: bundle.Clusters[name]}
+		var local interface{}
+		if existsInStringSlice(name, localClusters) {
+			if local, err = LoadClusterConfiguration(configDir, name); err != nil {
+				return plan, fmt.Errorf("cluster %s: %w", name, err)
+			}
+		}
+		if err := planEntry(&entry, local, strategy, &takenClusterNames); err != nil {
+			return plan, err
+		}
+		if entry.TargetName != name {
+			clusterRenames[name] = entry.TargetName
+		}
+		plan.Entries = append(plan.Entries, entry)
+	}
+
+	localContexts, err := ListContextConfigurations(configDir)
+	if err != nil {
+		return plan, err
+	}
+	takenContextNames := append([]string{}, localContexts...)
+	for _, name := range sortedKeys(bundle.Contexts) {
+		if len(contexts) > 0 && !existsInStringSlice(name, contexts) {
+			continue
+		}
+		context := bundle.Contexts[name]
+		// Follow the cluster, if it was renamed
+		if renamed, ok := clusterRenames[context.Cluster]; ok {
+			context.Cluster = renamed
+		}
+		entry := ImportPlanEntry{Kind: "context", Name: name, TargetName: name, contents: context}
+		var local interface{}
+		if existsInStringSlice(name, localContexts) {
+			if local, err = LoadContextConfiguration(configDir, name); err != nil {
+				return plan, fmt.Errorf("context %s: %w", name, err)
+			}
+		}
+		if err := planEntry(&entry, local, strategy, &takenContextNames); err != nil {
+			return plan, err
+		}
+		plan.Entries = append(plan.Entries, entry)
+	}
+
+	// The base configuration is imported only when there is none, otherwise just the current context
+	// is set, if missing
+	baseConfig, err := LoadBaseConfiguration(configDir)
+	var local interface{}
+	if err == nil {
+		local = baseConfig
+	} else {
+		baseConfig = bundle.BaseConfig
+		baseConfig.CurrentContext = ""
+	}
+	if baseConfig.CurrentContext == "" && bundle.BaseConfig.CurrentContext != "" {
+		for _, e := range plan.Entries {
+			if e.Kind == "context" && e.Name == bundle.BaseConfig.CurrentContext {
+				baseConfig.CurrentContext = e.TargetName
+			}
+		}
+	}
+	entry := ImportPlanEntry{Kind: "config", Name: baseConfigName, TargetName: baseConfigName, contents: baseConfig}
+	// Changes to the base configuration never conflict, they only fill in what's missing
+	if err := planEntry(&entry, local, PreferImportStrategy, nil); err != nil {
+		return plan, err
+	}
+	plan.Entries = append(plan.Entries, entry)
+
+	return plan, nil
+}
+
+// planEntry decides the action for entry, given the local entry with the same name, if any
+func planEntry(entry *ImportPlanEntry, local interface{}, strategy ImportStrategy, takenNames *[]string) error {
+	if local == nil {
+		entry.Action = ImportAdd
+		if takenNames != nil {
+			*takenNames = append(*takenNames, entry.Name)
+		}
+		return nil
+	}
+	if reflect.DeepEqual(local, entry.contents) {
+		entry.Action = ImportUnchanged
+		return nil
+	}
+
+	changes, err := diffFields(local, entry.contents)
+	if err != nil {
+		return err
+	}
+	entry.Changes = changes
+	switch strategy {
+	case KeepLocalStrategy:
+		entry.Action = ImportKeepLocal
+	case PreferImportStrategy:
+		entry.Action = ImportChange
+	case RenameConflictsStrategy:
+		entry.Action = ImportRename
+		entry.TargetName = freeName(entry.Name+"-imported", *takenNames)
+		*takenNames = append(*takenNames, entry.TargetName)
+	default:
+		return fmt.Errorf("invalid import strategy %q", strategy)
+	}
+	return nil
+}
+
+// freeName returns name, or name followed by a number, so that it's not in takenNames
+func freeName(name string, takenNames []string) string {
+	candidate := name
+	for i := 2; existsInStringSlice(candidate, takenNames); i++ {
+		candidate = fmt.Sprintf("%s-%d", name, i)
+	}
+	return candidate
+}
+
+// diffFields returns the fields which differ between local and imported, hiding secrets
+func diffFields(local, imported interface{}) ([]FieldChange, error) {
+	localFields, err := flattenFields(local)
+	if err != nil {
+		return nil, err
+	}
+	importedFields, err := flattenFields(imported)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := []string{}
+	for field := range localFields {
+		fields = append(fields, field)
+	}
+	for field := range importedFields {
+		if _, ok := localFields[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	changes := []FieldChange{}
+	for _, field := range fields {
+		if localFields[field] == importedFields[field] {
+			continue
+		}
+		change := FieldChange{Field: field, Local: localFields[field], Imported: importedFields[field]}
+		if isSecretField(field) {
+			change.Local = hideSecret(change.Local)
+			change.Imported = hideSecret(change.Imported)
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// flattenFields returns the fields of v with their values, keyed by their dotted path
+func flattenFields(v interface{}) (map[string]string, error) {
+	jsonBytes, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(jsonBytes, &generic); err != nil {
+		return nil, err
+	}
+
+	fields := map[string]string{}
+	var flatten func(prefix string, value interface{})
+	flatten = func(prefix string, value interface{}) {
+		switch value := value.(type) {
+		case map[string]interface{}:
+			for k, v := range value {
+				flatten(strings.TrimPrefix(prefix+"."+k, "."), v)
+			}
+		case []interface{}:
+			for i, v := range value {
+				flatten(fmt.Sprintf("%s[%d]", prefix, i), v)
+			}
+		case nil:
+		default:
+			fields[prefix] = fmt.Sprint(value)
+		}
+	}
+	flatten("", generic)
+	return fields, nil
+}
+
+func isSecretField(field string) bool {
+	last := field[strings.LastIndex(field, ".")+1:]
+	return last == "key" || last == "token" || last == "key-check"
+}
+
+func hideSecret(value string) string {
+	if value == "" {
+		return ""
+	}
+	return "(hidden)"
+}
+
+// ApplyImportPlan imports the entries of plan into configDir, or into the first configuration file
+// when they are used. Either all the entries are written, or none is.
+func ApplyImportPlan(plan ImportPlan, configDir string) error {
+	lock, unlock, err := lockConfigDir(nil, configDir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries := []ImportPlanEntry{}
+	for _, entry := range plan.Entries {
+		switch entry.Action {
+		case ImportAdd, ImportChange, ImportRename:
+		default:
+			continue
+		}
+
+		switch contents := entry.contents.(type) {
+		case ClusterFile:
+			if err := encryptKeyIfNeeded(configDir, &contents.Housekeeping.Key); err != nil {
+				return err
+			}
+			entry.contents = contents
+		case ContextFile:
+			if err := encryptKeyIfNeeded(configDir, &contents.Realm.Key); err != nil {
+				return err
+			}
+			entry.contents = contents
+		case BaseConfigFile:
+		default:
+			return fmt.Errorf("unknown entry kind %q", entry.Kind)
+		}
+		entries = append(entries, entry)
+	}
+
+	if files := GetConfigFiles(); len(files) > 0 {
+		// A single file is written, atomically
+		return updateConfigFile(lock, files[0], func(bundle *Bundle) error {
+			for _, entry := range entries {
+				switch contents := entry.contents.(type) {
+				case ClusterFile:
+					bundle.Clusters[entry.TargetName] = contents
+				case ContextFile:
+					bundle.Contexts[entry.TargetName] = contents
+				case BaseConfigFile:
+					bundle.BaseConfig = contents
+				}
+			}
+			return nil
+		})
+	}
+
+	if err := ensureConfigDirectoryStructure(configDir); err != nil {
+		return err
+	}
+	files := []pendingFile{}
+	for _, entry := range entries {
+		dir := configDir
+		hasSecrets := false
+		switch contents := entry.contents.(type) {
+		case ClusterFile:
+			dir = clustersDirFromConfigDir(configDir)
+			hasSecrets = contents.Housekeeping.Key != "" || contents.Housekeeping.Token != ""
+		case ContextFile:
+			dir = contextsDirFromConfigDir(configDir)
+			hasSecrets = contents.Realm.Key != "" || contents.Realm.Token != ""
+		}
+
+		marshaled, err := yaml.Marshal(entry.contents)
+		if err != nil {
+			return err
+		}
+		// Keep on using the existing file, whatever its extension
+		fileName, err := getYamlFilename(dir, entry.TargetName)
+		if err != nil {
+			fileName = path.Join(dir, entry.TargetName+".yaml")
+		}
+		files = append(files, pendingFile{name: fileName, contents: marshaled, mode: fileModeFor(hasSecrets)})
+	}
+	return writeFilesAtomically(files)
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,178 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+// importTestConfigDir returns a config directory with clusters local and prod, context dev and
+// dev as current context
+func importTestConfigDir(t *testing.T) string {
+	t.Helper()
+	t.Setenv(configFileEnv, "")
+	configDir := t.TempDir()
+	clusters := map[string]ClusterFile{
+		"local":         {URL: "https://api.astarte.localhost"},
+		"prod":          {URL: "https://api.astarte.example.com"},
+		"prod-imported": {URL: "https://api.old.example.com"},
+	}
+	for name, cluster := range clusters {
+		if err := SaveClusterConfiguration(configDir, name, cluster, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := SaveContextConfiguration(configDir, "dev", ContextFile{Cluster: "local", Realm: RealmConfiguration{Name: "test"}}, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveBaseConfiguration(configDir, BaseConfigFile{CurrentContext: "dev"}); err != nil {
+		t.Fatal(err)
+	}
+	return configDir
+}
+
+func importTestBundle() Bundle {
+	return Bundle{
+		BaseConfig: BaseConfigFile{CurrentContext: "staging"},
+		Clusters: map[string]ClusterFile{
+			"local":   {URL: "https://api.astarte.localhost"},
+			"prod":    {URL: "https://api.astarte.example.org"},
+			"staging": {URL: "https://api.staging.example.com"},
+		},
+		Contexts: map[string]ContextFile{
+			"dev":     {Cluster: "prod", Realm: RealmConfiguration{Name: "test"}},
+			"staging": {Cluster: "staging", Realm: RealmConfiguration{Name: "test"}},
+		},
+	}
+}
+
+// planSummary describes an ImportPlanEntry as kind/name -> action target_name
+type planSummary map[string]string
+
+func summarize(plan ImportPlan) planSummary {
+	summary := planSummary{}
+	for _, e := range plan.Entries {
+		summary[e.Kind+"/"+e.Name] = string(e.Action) + " " + e.TargetName
+	}
+	return summary
+}
+
+func TestPlanBundleImport(t *testing.T) {
+	tests := []struct {
+		strategy ImportStrategy
+		want     planSummary
+	}{
+		{
+			strategy: KeepLocalStrategy,
+			want: planSummary{
+				"cluster/local":     "unchanged local",
+				"cluster/prod":      "keep-local prod",
+				"cluster/staging":   "add staging",
+				"context/dev":       "keep-local dev",
+				"context/staging":   "add staging",
+				"config/astartectl": "unchanged astartectl",
+			},
+		},
+		{
+			strategy: PreferImportStrategy,
+			want: planSummary{
+				"cluster/local":     "unchanged local",
+				"cluster/prod":      "change prod",
+				"cluster/staging":   "add staging",
+				"context/dev":       "change dev",
+				"context/staging":   "add staging",
+				"config/astartectl": "unchanged astartectl",
+			},
+		},
+		{
+			strategy: RenameConflictsStrategy,
+			want: planSummary{
+				"cluster/local":     "unchanged local",
+				"cluster/prod":      "rename prod-imported-2",
+				"cluster/staging":   "add staging",
+				"context/dev":       "rename dev-imported",
+				"context/staging":   "add staging",
+				"config/astartectl": "unchanged astartectl",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			configDir := importTestConfigDir(t)
+			plan, err := PlanBundleImport(importTestBundle(), configDir, nil, nil, tt.strategy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := summarize(plan); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PlanBundleImport() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanBundleImportChanges(t *testing.T) {
+	configDir := importTestConfigDir(t)
+	plan, err := PlanBundleImport(importTestBundle(), configDir, nil, nil, RenameConflictsStrategy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, e := range plan.Entries {
+		switch e.Kind + "/" + e.Name {
+		case "cluster/prod":
+			want := []FieldChange{{Field: "url", Local: "https://api.astarte.example.com", Imported: "https://api.astarte.example.org"}}
+			if !reflect.DeepEqual(e.Changes, want) {
+				t.Errorf("changes of cluster prod = %+v, want %+v", e.Changes, want)
+			}
+		case "context/dev":
+			// The context follows its renamed cluster
+			want := []FieldChange{{Field: "cluster", Local: "local", Imported: "prod-imported-2"}}
+			if !reflect.DeepEqual(e.Changes, want) {
+				t.Errorf("changes of context dev = %+v, want %+v", e.Changes, want)
+			}
+		}
+	}
+}
+
+func TestPlanBundleImportSelection(t *testing.T) {
+	configDir := importTestConfigDir(t)
+	plan, err := PlanBundleImport(importTestBundle(), configDir, []string{"staging"}, []string{"staging"}, KeepLocalStrategy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := planSummary{
+		"cluster/staging":   "add staging",
+		"context/staging":   "add staging",
+		"config/astartectl": "unchanged astartectl",
+	}
+	if got := summarize(plan); !reflect.DeepEqual(got, want) {
+		t.Errorf("PlanBundleImport() = %v, want %v", got, want)
+	}
+}
+
+func TestPlanBundleImportEmptyConfigDir(t *testing.T) {
+	t.Setenv(configFileEnv, "")
+	plan, err := PlanBundleImport(importTestBundle(), t.TempDir(), nil, nil, KeepLocalStrategy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range plan.Entries {
+		if e.Action != ImportAdd {
+			t.Errorf("%s %s: action = %s, want %s", e.Kind, e.Name, e.Action, ImportAdd)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"filippo.io/age"
+)
+
+func testBundle() Bundle {
+	return Bundle{
+		BaseConfig: BaseConfigFile{CurrentContext: "dev"},
+		Clusters: map[string]ClusterFile{
+			"local": {URL: "https://api.astarte.localhost", Housekeeping: HousekeepingConfiguration{Key: "aGtrZXk="}},
+		},
+		Contexts: map[string]ContextFile{
+			"dev": {Cluster: "local", Realm: RealmConfiguration{Name: "test", Key: "cmVhbG1rZXk="}},
+		},
+	}
+}
+
+func TestEncryptBundleWithPassphrase(t *testing.T) {
+	recipient, err := age.NewScryptRecipient("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Keep the test fast
+	recipient.SetWorkFactor(10)
+	encrypted, err := EncryptBundle(testBundle(), recipient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEncryptedBundle(encrypted) {
+		t.Fatal("the bundle is not recognized as encrypted")
+	}
+
+	identity, err := age.NewScryptIdentity("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseBundle(encrypted, identity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed, testBundle()) {
+		t.Errorf("ParseBundle() = %+v, want %+v", parsed, testBundle())
+	}
+
+	wrong, err := age.NewScryptIdentity("wrong")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseBundle(encrypted, wrong); err == nil {
+		t.Error("ParseBundle() succeeded with the wrong passphrase")
+	}
+}
+
+func TestEncryptBundleWithRecipients(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := EncryptBundle(testBundle(), identity.Recipient())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := ParseBundle(encrypted, other, identity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed, testBundle()) {
+		t.Errorf("ParseBundle() = %+v, want %+v", parsed, testBundle())
+	}
+	if _, err := ParseBundle(encrypted, other); err == nil {
+		t.Error("ParseBundle() succeeded without the right identity")
+	}
+}
+
+func TestParseBundlePlain(t *testing.T) {
+	contents, err := json.Marshal(testBundle())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if IsEncryptedBundle(contents) {
+		t.Fatal("a plain bundle is recognized as encrypted")
+	}
+	parsed, err := ParseBundle(contents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed, testBundle()) {
+		t.Errorf("ParseBundle() = %+v, want %+v", parsed, testBundle())
+	}
+}
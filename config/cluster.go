@@ -42,6 +42,31 @@ type HousekeepingConfiguration struct {
 	Key string `yaml:"key,omitempty" json:"key,omitempty"`
 	// Token is a token used to authenticate against the realm. When set, it takes precedence over key
 	Token string `yaml:"token,omitempty" json:"token,omitempty"`
+	// TokenCommand is a command providing a token used to authenticate against housekeeping. Can be omitted
+	TokenCommand *ExecCredentialConfiguration `yaml:"token-command,omitempty" json:"token-command,omitempty"`
+	// KeyCommand is a command providing the housekeeping private key. Can be omitted
+	KeyCommand *ExecCredentialConfiguration `yaml:"key-command,omitempty" json:"key-command,omitempty"`
+}
+
+// RetryConfiguration represents the retry policy used when talking to a Cluster's APIs.
+// Empty fields fall back to the global settings
+type RetryConfiguration struct {
+	// MaxAttempts is the maximum number of attempts for a single request. 1 disables retries
+	MaxAttempts int `yaml:"max-attempts,omitempty" json:"max-attempts,omitempty"`
+	// Backoff is the time to wait before the first retry, as a duration (e.g. 500ms). It doubles at each retry
+	Backoff string `yaml:"backoff,omitempty" json:"backoff,omitempty"`
+	// MaxBackoff is the maximum time to wait between two attempts, as a duration (e.g. 10s)
+	MaxBackoff string `yaml:"max-backoff,omitempty" json:"max-backoff,omitempty"`
+}
+
+// TLSConfiguration represents the TLS settings used when connecting to a Cluster's APIs
+type TLSConfiguration struct {
+	// CAFile is the path to a PEM bundle of CAs trusted in addition to the system ones
+	CAFile string `yaml:"ca-file,omitempty" json:"ca-file,omitempty"`
+	// ClientCert is the path to a PEM client certificate presented to the Cluster. Requires ClientKey
+	ClientCert string `yaml:"client-cert,omitempty" json:"client-cert,omitempty"`
+	// ClientKey is the path to the PEM private key of ClientCert
+	ClientKey string `yaml:"client-key,omitempty" json:"client-key,omitempty"`
 }
 
 // ClusterFile represents a Cluster file
@@ -54,16 +79,36 @@ type ClusterFile struct {
 	// be used instead, but in case of exotic API server setups they are required. When specified, they
 	// take precedence over URL
 	IndividualURLs IndividualURLsConfiguration `yaml:"individual-urls,omitempty" json:"individual-urls,omitempty"`
+	// Retry overrides the retry policy for requests towards the Cluster. Can be omitted to use the global settings
+	Retry RetryConfiguration `yaml:"retry,omitempty" json:"retry,omitempty"`
+	// TLS holds the TLS settings for the Cluster. Can be omitted when the Cluster uses a publicly trusted certificate
+	TLS TLSConfiguration `yaml:"tls,omitempty" json:"tls,omitempty"`
 }
 
 // ListClusterConfigurations returns a list of available cluster configurations
 func ListClusterConfigurations(configDir string) ([]string, error) {
+	if files := GetConfigFiles(); len(files) > 0 {
+		bundle, err := mergedConfigFiles(files)
+		return sortedKeys(bundle.Clusters), err
+	}
 	return listYamlNames(clustersDirFromConfigDir(configDir))
 }
 
 // LoadClusterConfiguration loads a cluster configuration from the config directory
 func LoadClusterConfiguration(configDir, clusterName string) (ClusterFile, error) {
 	cluster := ClusterFile{}
+	if files := GetConfigFiles(); len(files) > 0 {
+		bundle, err := mergedConfigFiles(files)
+		if err != nil {
+			return cluster, err
+		}
+		cluster, ok := bundle.Clusters[clusterName]
+		if !ok {
+			return cluster, os.ErrNotExist
+		}
+		return cluster, nil
+	}
+
 	contents, err := loadYamlFile(clustersDirFromConfigDir(configDir), clusterName)
 	if err != nil {
 		return cluster, err
@@ -74,8 +119,33 @@ func LoadClusterConfiguration(configDir, clusterName string) (ClusterFile, error
 
 // SaveClusterConfiguration saves a cluster configuration in the config directory
 func SaveClusterConfiguration(configDir, clusterName string, configuration ClusterFile, overwrite bool) error {
+	return saveClusterConfiguration(nil, configDir, clusterName, configuration, overwrite)
+}
+
+// saveClusterConfiguration is SaveClusterConfiguration, called while holding held, if not nil
+func saveClusterConfiguration(held *configLock, configDir, clusterName string, configuration ClusterFile, overwrite bool) error {
 	configPath := path.Join(clustersDirFromConfigDir(configDir), clusterName+".yaml")
 
+	lock, unlock, err := lockConfigDir(held, configDir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if files := GetConfigFiles(); len(files) > 0 {
+		defines := func(bundle Bundle) bool {
+			_, ok := bundle.Clusters[clusterName]
+			return ok
+		}
+		return saveToConfigFiles(lock, files, defines, overwrite, func(bundle *Bundle) error {
+			if err := encryptKeyIfNeeded(configDir, &configuration.Housekeeping.Key); err != nil {
+				return err
+			}
+			bundle.Clusters[clusterName] = configuration
+			return nil
+		})
+	}
+
 	if !overwrite {
 		if _, err := os.Stat(configPath); err == nil {
 			// Don't overwrite, don't fail
@@ -87,16 +157,35 @@ func SaveClusterConfiguration(configDir, clusterName string, configuration Clust
 		return err
 	}
 
+	if err := encryptKeyIfNeeded(configDir, &configuration.Housekeeping.Key); err != nil {
+		return err
+	}
+
 	contents, err := yaml.Marshal(configuration)
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(configPath, contents, 0644)
+	hasSecrets := configuration.Housekeeping.Key != "" || configuration.Housekeeping.Token != ""
+	return writeFileAtomically(configPath, contents, fileModeFor(hasSecrets))
 }
 
 // DeleteClusterConfiguration deletes a cluster configuration in the config directory. It will return
 // an error if the cluster does not exist. The operation cannot be reverted
 func DeleteClusterConfiguration(configDir, clusterName string) error {
+	lock, unlock, err := lockConfigDir(nil, configDir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if files := GetConfigFiles(); len(files) > 0 {
+		defines := func(bundle Bundle) bool {
+			_, ok := bundle.Clusters[clusterName]
+			return ok
+		}
+		return deleteFromConfigFiles(lock, files, defines, func(bundle *Bundle) { delete(bundle.Clusters, clusterName) })
+	}
+
 	fileName, err := getYamlFilename(clustersDirFromConfigDir(configDir), clusterName)
 	if err != nil {
 		return err
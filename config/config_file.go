@@ -0,0 +1,250 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
+)
+
+// configFileEnv is the environment variable listing configuration files, separated like PATH
+const configFileEnv = "ASTARTECTL_CONFIG"
+
+// GetConfigFiles returns the configuration files set through --config-file or ASTARTECTL_CONFIG,
+// separated like PATH. When any is set, clusters, contexts and the base configuration are read from
+// them, in the same format as config export, instead of from the config directory.
+func GetConfigFiles() []string {
+	value := viper.GetString("config-file")
+	if value == "" {
+		value = os.Getenv(configFileEnv)
+	}
+
+	files := []string{}
+	for _, f := range filepath.SplitList(value) {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// LoadConfigFiles returns the merged configuration of fileNames. Like KUBECONFIG, the first file
+// setting a value wins: clusters and contexts come from the first file defining them, and each
+// setting of the base configuration from the first file setting it. Missing files are skipped, but
+// at least one has to exist.
+func LoadConfigFiles(fileNames []string) (Bundle, error) {
+	merged := Bundle{
+		Clusters: map[string]ClusterFile{},
+		Contexts: map[string]ContextFile{},
+	}
+
+	found := false
+	for _, fileName := range fileNames {
+		bundle, err := loadConfigFile(fileName)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return merged, err
+		}
+		found = true
+
+		if merged.BaseConfig.CurrentContext == "" {
+			merged.BaseConfig.CurrentContext = bundle.BaseConfig.CurrentContext
+		}
+		if merged.BaseConfig.KeyCheck == "" {
+			merged.BaseConfig.KeyCheck = bundle.BaseConfig.KeyCheck
+		}
+		if merged.BaseConfig.AuditLog == "" {
+			merged.BaseConfig.AuditLog = bundle.BaseConfig.AuditLog
+		}
+		for name, cluster := range bundle.Clusters {
+			if _, ok := merged.Clusters[name]; !ok {
+				merged.Clusters[name] = cluster
+			}
+		}
+		for name, context := range bundle.Contexts {
+			if _, ok := merged.Contexts[name]; !ok {
+				merged.Contexts[name] = context
+			}
+		}
+	}
+
+	if !found {
+		return merged, &os.PathError{Op: "open", Path: strings.Join(fileNames, string(os.PathListSeparator)), Err: os.ErrNotExist}
+	}
+	return merged, nil
+}
+
+// loadConfigFile loads a single configuration file, in YAML or JSON
+func loadConfigFile(fileName string) (Bundle, error) {
+	bundle := Bundle{}
+	contents, err := os.ReadFile(fileName)
+	if err != nil {
+		return bundle, err
+	}
+	if err := yaml.Unmarshal(contents, &bundle); err != nil {
+		return bundle, fmt.Errorf("%s: %w", fileName, err)
+	}
+	if bundle.Clusters == nil {
+		bundle.Clusters = map[string]ClusterFile{}
+	}
+	if bundle.Contexts == nil {
+		bundle.Contexts = map[string]ContextFile{}
+	}
+	return bundle, nil
+}
+
+// updateConfigFile applies update to the configuration file fileName, creating it if needed.
+// The file is locked while being updated, unless held is already a lock on it, and replaced
+// atomically. Files with a .json extension are kept in JSON, any other in YAML.
+func updateConfigFile(held *configLock, fileName string, update func(*Bundle) error) error {
+	_, unlock, err := relock(held, fileName+".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	bundle, err := loadConfigFile(fileName)
+	if errors.Is(err, os.ErrNotExist) {
+		bundle = Bundle{Clusters: map[string]ClusterFile{}, Contexts: map[string]ContextFile{}}
+	} else if err != nil {
+		return err
+	}
+	if err := update(&bundle); err != nil {
+		return err
+	}
+
+	var contents []byte
+	if strings.EqualFold(filepath.Ext(fileName), ".json") {
+		contents, err = json.MarshalIndent(bundle, "", "  ")
+	} else {
+		contents, err = yaml.Marshal(bundle)
+	}
+	if err != nil {
+		return err
+	}
+	return writeFileAtomically(fileName, contents, fileModeFor(bundleHasSecrets(bundle)))
+}
+
+// mergedConfigFiles is LoadConfigFiles, returning an empty configuration when no file exists
+func mergedConfigFiles(fileNames []string) (Bundle, error) {
+	bundle, err := LoadConfigFiles(fileNames)
+	if errors.Is(err, os.ErrNotExist) {
+		return bundle, nil
+	}
+	return bundle, err
+}
+
+// saveToConfigFiles saves an entry with save, in the file already defining it or in the first one.
+// Existing entries are left untouched unless overwrite is true.
+func saveToConfigFiles(held *configLock, fileNames []string, defines func(Bundle) bool, overwrite bool, save func(*Bundle) error) error {
+	fileName, defined := configFileDefining(fileNames, defines)
+	if defined && !overwrite {
+		// Don't overwrite, don't fail
+		return nil
+	}
+	return updateConfigFile(held, fileName, save)
+}
+
+// deleteFromConfigFiles deletes an entry with remove, from the file defining it
+func deleteFromConfigFiles(held *configLock, fileNames []string, defines func(Bundle) bool, remove func(*Bundle)) error {
+	fileName, defined := configFileDefining(fileNames, defines)
+	if !defined {
+		return os.ErrNotExist
+	}
+	return updateConfigFile(held, fileName, func(bundle *Bundle) error {
+		remove(bundle)
+		return nil
+	})
+}
+
+// configFileDefining returns the first of fileNames where defines is true, or the first file when
+// none is. New entries are saved to the first file, like kubectl does.
+func configFileDefining(fileNames []string, defines func(Bundle) bool) (string, bool) {
+	for _, fileName := range fileNames {
+		if bundle, err := loadConfigFile(fileName); err == nil && defines(bundle) {
+			return fileName, true
+		}
+	}
+	return fileNames[0], false
+}
+
+func bundleHasSecrets(bundle Bundle) bool {
+	for _, cluster := range bundle.Clusters {
+		if cluster.Housekeeping.Key != "" || cluster.Housekeeping.Token != "" {
+			return true
+		}
+	}
+	for _, context := range bundle.Contexts {
+		if context.Realm.Key != "" || context.Realm.Token != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// configureViperFromFiles is ConfigureViper when configuration files are used
+func configureViperFromFiles(fileNames []string, contextOverride string) error {
+	bundle, err := LoadConfigFiles(fileNames)
+	if err != nil {
+		return err
+	}
+	if err := mergeIntoViper(bundle.BaseConfig); err != nil {
+		return err
+	}
+
+	currentContext, err := resolveCurrentContext(bundle.BaseConfig.CurrentContext, contextOverride)
+	if err != nil {
+		return err
+	}
+	currentContextName = currentContext
+
+	context, ok := bundle.Contexts[currentContext]
+	if !ok {
+		return fmt.Errorf("context %s not found in %s", currentContext, strings.Join(fileNames, ", "))
+	}
+	if err := mergeIntoViper(context); err != nil {
+		return err
+	}
+
+	if context.Cluster == "" {
+		return errors.New("No cluster defined in context - something is wrong")
+	}
+	cluster, ok := bundle.Clusters[context.Cluster]
+	if !ok {
+		return fmt.Errorf("cluster %s not found in %s", context.Cluster, strings.Join(fileNames, ", "))
+	}
+	return mergeIntoViper(cluster)
+}
+
+// mergeIntoViper merges a configuration file struct into Viper, with the same keys as its YAML
+func mergeIntoViper(v interface{}) error {
+	contents, err := yaml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	settings := map[string]interface{}{}
+	if err := yaml.Unmarshal(contents, &settings); err != nil {
+		return err
+	}
+	return viper.MergeConfigMap(settings)
+}
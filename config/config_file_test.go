@@ -0,0 +1,128 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, fileName, contents string) {
+	t.Helper()
+	if err := os.WriteFile(fileName, []byte(contents), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadConfigFilesPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.yaml")
+	second := filepath.Join(dir, "second.json")
+	writeTestFile(t, first, `
+config:
+  context: dev
+clusters:
+  local:
+    url: https://first.example.com
+contexts:
+  dev:
+    cluster: local
+    realm:
+      name: first
+`)
+	writeTestFile(t, second, `{
+  "config": {"context": "prod", "audit-log": "/var/log/astartectl.log"},
+  "clusters": {
+    "local": {"url": "https://second.example.com"},
+    "prod": {"url": "https://prod.example.com"}
+  },
+  "contexts": {
+    "dev": {"cluster": "local", "realm": {"name": "second"}},
+    "prod": {"cluster": "prod", "realm": {"name": "prod"}}
+  }
+}`)
+
+	bundle, err := LoadConfigFiles([]string{first, filepath.Join(dir, "missing.yaml"), second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bundle.BaseConfig.CurrentContext != "dev" {
+		t.Errorf("current context = %q, want the one of the first file", bundle.BaseConfig.CurrentContext)
+	}
+	if bundle.BaseConfig.AuditLog != "/var/log/astartectl.log" {
+		t.Errorf("audit log = %q, want the one of the second file, as the first doesn't set it", bundle.BaseConfig.AuditLog)
+	}
+	if got := bundle.Clusters["local"].URL; got != "https://first.example.com" {
+		t.Errorf("cluster local URL = %q, want the one of the first file", got)
+	}
+	if got := bundle.Clusters["prod"].URL; got != "https://prod.example.com" {
+		t.Errorf("cluster prod URL = %q, want the one of the second file", got)
+	}
+	if got := bundle.Contexts["dev"].Realm.Name; got != "first" {
+		t.Errorf("context dev realm = %q, want the one of the first file", got)
+	}
+	if _, ok := bundle.Contexts["prod"]; !ok {
+		t.Error("context prod of the second file is missing")
+	}
+}
+
+func TestLoadConfigFilesMissing(t *testing.T) {
+	dir := t.TempDir()
+	_, err := LoadConfigFiles([]string{filepath.Join(dir, "a.yaml"), filepath.Join(dir, "b.yaml")})
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("LoadConfigFiles() error = %v, want %v", err, os.ErrNotExist)
+	}
+}
+
+func TestSaveToConfigFiles(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.yaml")
+	second := filepath.Join(dir, "second.json")
+	writeTestFile(t, first, "clusters: {}\n")
+	writeTestFile(t, second, `{"clusters": {"prod": {"url": "https://prod.example.com"}}}`)
+	t.Setenv(configFileEnv, strings.Join([]string{first, second}, string(os.PathListSeparator)))
+
+	// Existing entries are updated in the file defining them, new ones go to the first file
+	if err := SaveClusterConfiguration(dir, "prod", ClusterFile{URL: "https://prod.example.org"}, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveClusterConfiguration(dir, "local", ClusterFile{URL: "https://api.astarte.localhost"}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	firstBundle, err := loadConfigFile(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := firstBundle.Clusters["local"]; !ok || len(firstBundle.Clusters) != 1 {
+		t.Errorf("clusters of the first file = %v, want only local", firstBundle.Clusters)
+	}
+
+	contents, err := os.ReadFile(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secondBundle := Bundle{}
+	if err := json.Unmarshal(contents, &secondBundle); err != nil {
+		t.Fatalf("the second file is not JSON anymore: %s", err)
+	}
+	if got := secondBundle.Clusters["prod"].URL; got != "https://prod.example.org" {
+		t.Errorf("cluster prod URL = %q, want the updated one", got)
+	}
+}
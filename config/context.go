@@ -21,6 +21,18 @@ import (
 	"gopkg.in/yaml.v2"
 )
 
+// ExecCredentialConfiguration represents an external command providing credentials. The command
+// is expected to print on stdout a JSON object such as {"token": "...", "expiration": "<RFC 3339 timestamp>"}
+// for a token, or {"key": "<PEM private key>"} for a key. A plain PEM key is accepted as well.
+type ExecCredentialConfiguration struct {
+	// Command is the executable to run, either an absolute path or a name looked up in PATH
+	Command string `yaml:"command" json:"command"`
+	// Args are the arguments passed to Command. Can be omitted
+	Args []string `yaml:"args,omitempty" json:"args,omitempty"`
+	// Env are additional environment variables set when running Command. Can be omitted
+	Env map[string]string `yaml:"env,omitempty" json:"env,omitempty"`
+}
+
 // RealmConfiguration represents configuration for a Realm
 type RealmConfiguration struct {
 	// Name is the name of the realm
@@ -29,6 +41,10 @@ type RealmConfiguration struct {
 	Key string `yaml:"key,omitempty" json:"key,omitempty"`
 	// Token is a token used to authenticate against the realm. When set, it takes precedence over key
 	Token string `yaml:"token,omitempty" json:"token,omitempty"`
+	// TokenCommand is a command providing a token used to authenticate against the realm. Can be omitted
+	TokenCommand *ExecCredentialConfiguration `yaml:"token-command,omitempty" json:"token-command,omitempty"`
+	// KeyCommand is a command providing the private key of the realm. Can be omitted
+	KeyCommand *ExecCredentialConfiguration `yaml:"key-command,omitempty" json:"key-command,omitempty"`
 }
 
 // ContextFile represents a Context file
@@ -41,12 +57,28 @@ type ContextFile struct {
 
 // ListContextConfigurations returns a list of available context configurations
 func ListContextConfigurations(configDir string) ([]string, error) {
+	if files := GetConfigFiles(); len(files) > 0 {
+		bundle, err := mergedConfigFiles(files)
+		return sortedKeys(bundle.Contexts), err
+	}
 	return listYamlNames(contextsDirFromConfigDir(configDir))
 }
 
 // LoadContextConfiguration loads a context configuration from the config directory
 func LoadContextConfiguration(configDir, contextName string) (ContextFile, error) {
 	context := ContextFile{}
+	if files := GetConfigFiles(); len(files) > 0 {
+		bundle, err := mergedConfigFiles(files)
+		if err != nil {
+			return context, err
+		}
+		context, ok := bundle.Contexts[contextName]
+		if !ok {
+			return context, os.ErrNotExist
+		}
+		return context, nil
+	}
+
 	contents, err := loadYamlFile(contextsDirFromConfigDir(configDir), contextName)
 	if err != nil {
 		return context, err
@@ -57,8 +89,33 @@ func LoadContextConfiguration(configDir, contextName string) (ContextFile, error
 
 // SaveContextConfiguration saves a context configuration in the config directory
 func SaveContextConfiguration(configDir, contextName string, configuration ContextFile, overwrite bool) error {
+	return saveContextConfiguration(nil, configDir, contextName, configuration, overwrite)
+}
+
+// saveContextConfiguration is SaveContextConfiguration, called while holding held, if not nil
+func saveContextConfiguration(held *configLock, configDir, contextName string, configuration ContextFile, overwrite bool) error {
 	configPath := path.Join(contextsDirFromConfigDir(configDir), contextName+".yaml")
 
+	lock, unlock, err := lockConfigDir(held, configDir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if files := GetConfigFiles(); len(files) > 0 {
+		defines := func(bundle Bundle) bool {
+			_, ok := bundle.Contexts[contextName]
+			return ok
+		}
+		return saveToConfigFiles(lock, files, defines, overwrite, func(bundle *Bundle) error {
+			if err := encryptKeyIfNeeded(configDir, &configuration.Realm.Key); err != nil {
+				return err
+			}
+			bundle.Contexts[contextName] = configuration
+			return nil
+		})
+	}
+
 	if !overwrite {
 		if _, err := os.Stat(configPath); err == nil {
 			// Don't overwrite, don't fail
@@ -70,16 +127,35 @@ func SaveContextConfiguration(configDir, contextName string, configuration Conte
 		return err
 	}
 
+	if err := encryptKeyIfNeeded(configDir, &configuration.Realm.Key); err != nil {
+		return err
+	}
+
 	contents, err := yaml.Marshal(configuration)
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(configPath, contents, 0644)
+	hasSecrets := configuration.Realm.Key != "" || configuration.Realm.Token != ""
+	return writeFileAtomically(configPath, contents, fileModeFor(hasSecrets))
 }
 
 // DeleteContextConfiguration deletes a context configuration in the config directory. It will return
 // an error if the context does not exist. The operation cannot be reverted
 func DeleteContextConfiguration(configDir, contextName string) error {
+	lock, unlock, err := lockConfigDir(nil, configDir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if files := GetConfigFiles(); len(files) > 0 {
+		defines := func(bundle Bundle) bool {
+			_, ok := bundle.Contexts[contextName]
+			return ok
+		}
+		return deleteFromConfigFiles(lock, files, defines, func(bundle *Bundle) { delete(bundle.Contexts, contextName) })
+	}
+
 	fileName, err := getYamlFilename(contextsDirFromConfigDir(configDir), contextName)
 	if err != nil {
 		return err
@@ -0,0 +1,309 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/term"
+)
+
+const (
+	// encryptedKeyPrefix marks a key field encrypted by astartectl. The version allows changing
+	// the scheme in the future.
+	encryptedKeyPrefix = "encrypted:v1:"
+	// keyPassphraseEnv is the environment variable holding the passphrase of encrypted keys
+	keyPassphraseEnv = "ASTARTECTL_KEY_PASSPHRASE"
+	// keyCheckPlaintext is encrypted in the base configuration to check passphrases
+	keyCheckPlaintext = "astartectl"
+
+	saltSize = 16
+	// scrypt parameters recommended for interactive logins as of 2017
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// ErrWrongPassphrase is returned when a key can't be decrypted with the given passphrase
+var ErrWrongPassphrase = errors.New("wrong passphrase")
+
+var cachedKeyPassphrase string
+
+// IsEncryptedKey returns true if key has been encrypted with EncryptKey
+func IsEncryptedKey(key string) bool {
+	return strings.HasPrefix(key, encryptedKeyPrefix)
+}
+
+// EncryptKey encrypts a key field with AES-GCM, using a key derived from passphrase through scrypt.
+func EncryptKey(key, passphrase string) (string, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	aead, err := newKeyAEAD(passphrase, salt)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	// Layout is salt | nonce | ciphertext
+	sealed := append(salt, nonce...)
+	sealed = aead.Seal(sealed, nonce, []byte(key), nil)
+	return encryptedKeyPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptKey decrypts a key field encrypted with EncryptKey. Keys which are not encrypted are returned as is.
+func DecryptKey(key, passphrase string) (string, error) {
+	if !IsEncryptedKey(key) {
+		return key, nil
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(key, encryptedKeyPrefix))
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < saltSize {
+		return "", errors.New("encrypted key is too short")
+	}
+	aead, err := newKeyAEAD(passphrase, sealed[:saltSize])
+	if err != nil {
+		return "", err
+	}
+	sealed = sealed[saltSize:]
+	if len(sealed) < aead.NonceSize() {
+		return "", errors.New("encrypted key is too short")
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", ErrWrongPassphrase
+	}
+	return string(plaintext), nil
+}
+
+// DecodeKey returns the PEM contents of a key field, decrypting it first if needed.
+func DecodeKey(key string) ([]byte, error) {
+	if IsEncryptedKey(key) {
+		passphrase, err := KeyPassphrase(false)
+		if err != nil {
+			return nil, err
+		}
+		if key, err = DecryptKey(key, passphrase); err != nil {
+			return nil, err
+		}
+	}
+	return base64.StdEncoding.DecodeString(key)
+}
+
+// KeyPassphrase returns the passphrase of encrypted keys, reading it from the ASTARTECTL_KEY_PASSPHRASE
+// environment variable or prompting the user. When confirm is true, the user is asked to type it twice.
+// The passphrase is asked only once per invocation.
+func KeyPassphrase(confirm bool) (string, error) {
+	if cachedKeyPassphrase != "" {
+		return cachedKeyPassphrase, nil
+	}
+	if passphrase, ok := os.LookupEnv(keyPassphraseEnv); ok && passphrase != "" {
+		cachedKeyPassphrase = passphrase
+		return passphrase, nil
+	}
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("a passphrase is required to access encrypted keys, set it through %s", keyPassphraseEnv)
+	}
+	passphrase, err := readPassphrase("Passphrase for astartectl keys: ")
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", errors.New("passphrase can't be empty")
+	}
+	if confirm {
+		confirmation, err := readPassphrase("Confirm passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		if confirmation != passphrase {
+			return "", errors.New("passphrases don't match")
+		}
+	}
+
+	cachedKeyPassphrase = passphrase
+	return passphrase, nil
+}
+
+// KeysEncrypted returns true if keys in configDir are stored encrypted
+func KeysEncrypted(configDir string) bool {
+	baseConfig, err := LoadBaseConfiguration(configDir)
+	return err == nil && baseConfig.KeyCheck != ""
+}
+
+// keyPassphraseForConfigDir returns the passphrase of the keys in configDir, checking it is the right one
+func keyPassphraseForConfigDir(baseConfig BaseConfigFile) (string, error) {
+	passphrase, err := KeyPassphrase(false)
+	if err != nil {
+		return "", err
+	}
+	if _, err := DecryptKey(baseConfig.KeyCheck, passphrase); err != nil {
+		// Don't keep on using a wrong passphrase
+		cachedKeyPassphrase = ""
+		return "", err
+	}
+	return passphrase, nil
+}
+
+// encryptKeyIfNeeded encrypts a key field in place when keys in configDir are stored encrypted
+func encryptKeyIfNeeded(configDir string, key *string) error {
+	if *key == "" || IsEncryptedKey(*key) {
+		return nil
+	}
+	baseConfig, err := LoadBaseConfiguration(configDir)
+	if err != nil || baseConfig.KeyCheck == "" {
+		return nil
+	}
+	passphrase, err := keyPassphraseForConfigDir(baseConfig)
+	if err != nil {
+		return err
+	}
+	*key, err = EncryptKey(*key, passphrase)
+	return err
+}
+
+func newKeyAEAD(passphrase string, salt []byte) (cipher.AEAD, error) {
+	derivedKey, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(derivedKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func readPassphrase(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	passphrase, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	return string(passphrase), err
+}
+
+// EncryptConfigDirectory enables encrypted storage of keys in configDir, and encrypts all the keys
+// already stored there with passphrase.
+func EncryptConfigDirectory(configDir, passphrase string) error {
+	lock, unlock, err := lockConfigDir(nil, configDir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	baseConfig, err := LoadBaseConfiguration(configDir)
+	if err != nil {
+		return err
+	}
+	if baseConfig.KeyCheck != "" {
+		return errors.New("keys are already encrypted")
+	}
+	if baseConfig.KeyCheck, err = EncryptKey(keyCheckPlaintext, passphrase); err != nil {
+		return err
+	}
+	// Enable encryption first of all: from now on, saved keys are encrypted
+	if err := saveBaseConfiguration(lock, configDir, baseConfig); err != nil {
+		return err
+	}
+	cachedKeyPassphrase = passphrase
+
+	return rewriteAllKeys(lock, configDir, func(key string) (string, error) { return key, nil })
+}
+
+// DecryptConfigDirectory disables encrypted storage of keys in configDir, and stores all its keys
+// in clear.
+func DecryptConfigDirectory(configDir, passphrase string) error {
+	lock, unlock, err := lockConfigDir(nil, configDir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	baseConfig, err := LoadBaseConfiguration(configDir)
+	if err != nil {
+		return err
+	}
+	if baseConfig.KeyCheck == "" {
+		return errors.New("keys are not encrypted")
+	}
+	if _, err := DecryptKey(baseConfig.KeyCheck, passphrase); err != nil {
+		return err
+	}
+	// Disable encryption first of all, so that keys are saved in clear
+	baseConfig.KeyCheck = ""
+	if err := saveBaseConfiguration(lock, configDir, baseConfig); err != nil {
+		return err
+	}
+
+	return rewriteAllKeys(lock, configDir, func(key string) (string, error) { return DecryptKey(key, passphrase) })
+}
+
+// rewriteAllKeys applies transform to all keys in configDir and saves them back, while holding lock
+func rewriteAllKeys(lock *configLock, configDir string, transform func(string) (string, error)) error {
+	contexts, err := ListContextConfigurations(configDir)
+	if err != nil {
+		return err
+	}
+	for _, name := range contexts {
+		context, err := LoadContextConfiguration(configDir, name)
+		if err != nil {
+			return err
+		}
+		if context.Realm.Key == "" {
+			continue
+		}
+		if context.Realm.Key, err = transform(context.Realm.Key); err != nil {
+			return fmt.Errorf("context %s: %w", name, err)
+		}
+		if err := saveContextConfiguration(lock, configDir, name, context, true); err != nil {
+			return err
+		}
+	}
+
+	clusters, err := ListClusterConfigurations(configDir)
+	if err != nil {
+		return err
+	}
+	for _, name := range clusters {
+		cluster, err := LoadClusterConfiguration(configDir, name)
+		if err != nil {
+			return err
+		}
+		if cluster.Housekeeping.Key == "" {
+			continue
+		}
+		if cluster.Housekeeping.Key, err = transform(cluster.Housekeeping.Key); err != nil {
+			return fmt.Errorf("cluster %s: %w", name, err)
+		}
+		if err := saveClusterConfiguration(lock, configDir, name, cluster, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// configFileMode is the mode of configuration files
+	configFileMode os.FileMode = 0644
+	// secretFileMode is the mode of files containing private keys or tokens
+	secretFileMode os.FileMode = 0600
+)
+
+// fileModeFor returns the mode of a configuration file, restricting it when it contains secrets
+func fileModeFor(hasSecrets bool) os.FileMode {
+	if hasSecrets {
+		return secretFileMode
+	}
+	return configFileMode
+}
+
+// writeFileAtomically writes contents to fileName through a temporary file which is then renamed,
+// so that fileName is never seen partially written
+func writeFileAtomically(fileName string, contents []byte, mode os.FileMode) error {
+	return writeFilesAtomically([]pendingFile{{name: fileName, contents: contents, mode: mode}})
+}
+
+// pendingFile is a file to be written by writeFilesAtomically
+type pendingFile struct {
+	name     string
+	contents []byte
+	mode     os.FileMode
+}
+
+// writeFilesAtomically writes all files, or none of them. Files are written to temporary files next
+// to them first, which are then renamed. When a rename fails, the files renamed so far are restored.
+func writeFilesAtomically(files []pendingFile) error {
+	tempNames := []string{}
+	removeTempFiles := func() {
+		for _, tempName := range tempNames {
+			_ = os.Remove(tempName)
+		}
+	}
+
+	for _, f := range files {
+		tempName, err := writeTempFile(f)
+		if err != nil {
+			removeTempFiles()
+			return err
+		}
+		tempNames = append(tempNames, tempName)
+	}
+
+	// Keep what's being replaced, to roll back. Nil entries are files which don't exist yet.
+	previous := make([]*pendingFile, len(files))
+	for i, f := range files {
+		info, err := os.Stat(f.name)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		var contents []byte
+		if err == nil {
+			contents, err = os.ReadFile(f.name)
+		}
+		if err != nil {
+			removeTempFiles()
+			return err
+		}
+		previous[i] = &pendingFile{name: f.name, contents: contents, mode: info.Mode().Perm()}
+	}
+
+	for i, f := range files {
+		if err := os.Rename(tempNames[i], f.name); err != nil {
+			for j := 0; j < i; j++ {
+				restoreFile(files[j].name, previous[j])
+			}
+			removeTempFiles()
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreFile puts back the previous version of fileName, through a temporary file like any other
+// write, or removes it if it didn't exist. This is best effort, as it is done while handling an error.
+func restoreFile(fileName string, previous *pendingFile) {
+	if previous == nil {
+		_ = os.Remove(fileName)
+		return
+	}
+	tempName, err := writeTempFile(*previous)
+	if err != nil {
+		return
+	}
+	if err := os.Rename(tempName, fileName); err != nil {
+		_ = os.Remove(tempName)
+	}
+}
+
+// writeTempFile writes the contents of f to a temporary file in the same directory, returning its name
+func writeTempFile(f pendingFile) (string, error) {
+	temp, err := os.CreateTemp(filepath.Dir(f.name), "."+filepath.Base(f.name)+".tmp-*")
+	if err != nil {
+		return "", err
+	}
+	if _, err := temp.Write(f.contents); err != nil {
+		temp.Close()
+		os.Remove(temp.Name())
+		return "", err
+	}
+	if err := temp.Sync(); err != nil {
+		temp.Close()
+		os.Remove(temp.Name())
+		return "", err
+	}
+	if err := temp.Chmod(f.mode); err != nil {
+		temp.Close()
+		os.Remove(temp.Name())
+		return "", err
+	}
+	if err := temp.Close(); err != nil {
+		os.Remove(temp.Name())
+		return "", err
+	}
+	return temp.Name(), nil
+}
@@ -0,0 +1,140 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	lockFileName = ".lock"
+	// lockTimeout is how long to wait for another astartectl process to release the config directory
+	lockTimeout = 30 * time.Second
+	// lockRetryInterval is how often the lock is tried while waiting
+	lockRetryInterval = 50 * time.Millisecond
+)
+
+// errLocked is returned by tryLockFile when the file is locked by another process
+var errLocked = errors.New("locked")
+
+// configLock is a lock on a config directory or configuration file. Locks are not reentrant: the
+// functions saving configuration while a lock is held receive it, and don't take it again.
+type configLock struct {
+	path string
+	file *os.File
+	mu   *sync.Mutex
+}
+
+var (
+	lockMutexesMutex sync.Mutex
+	// lockMutexes exclude goroutines from each other, as file locks are held by the whole process
+	lockMutexes = map[string]*sync.Mutex{}
+)
+
+// LockConfigDir locks configDir against changes by other goroutines and astartectl processes,
+// waiting for them to release it. It returns a function releasing the lock. When configuration
+// files are used, the first one is locked instead.
+func LockConfigDir(configDir string) (func(), error) {
+	_, unlock, err := lockConfigDir(nil, configDir)
+	return unlock, err
+}
+
+// lockConfigDir is LockConfigDir, returning held as is when it is already a lock on configDir
+func lockConfigDir(held *configLock, configDir string) (*configLock, func(), error) {
+	if files := GetConfigFiles(); len(files) > 0 {
+		return relock(held, files[0]+".lock")
+	}
+	if configDir == "" {
+		configDir = GetDefaultConfigDir()
+	}
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return nil, nil, err
+	}
+	return relock(held, filepath.Join(configDir, lockFileName))
+}
+
+// relock returns held if it is a lock on lockPath, and locks lockPath otherwise. The returned
+// function releases the lock only when it was taken here.
+func relock(held *configLock, lockPath string) (*configLock, func(), error) {
+	lockPath, err := filepath.Abs(lockPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if held != nil && held.path == lockPath {
+		return held, func() {}, nil
+	}
+	lock, err := lockFile(lockPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	return lock, lock.unlock, nil
+}
+
+// lockFile takes an exclusive lock on the file lockPath, creating it if needed, waiting for other
+// goroutines and processes to release it
+func lockFile(lockPath string) (*configLock, error) {
+	deadline := time.Now().Add(lockTimeout)
+	mu := lockMutex(lockPath)
+	for !mu.TryLock() {
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for %s to be released", lockPath)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+
+	f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, configFileMode)
+	if err != nil {
+		mu.Unlock()
+		return nil, err
+	}
+	for {
+		err := tryLockFile(f)
+		if err == nil {
+			return &configLock{path: lockPath, file: f, mu: mu}, nil
+		}
+		if !errors.Is(err, errLocked) || time.Now().After(deadline) {
+			f.Close()
+			mu.Unlock()
+			if errors.Is(err, errLocked) {
+				return nil, fmt.Errorf("timed out waiting for another astartectl process to release %s", lockPath)
+			}
+			return nil, err
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+// lockMutex returns the mutex guarding lockPath within this process
+func lockMutex(lockPath string) *sync.Mutex {
+	lockMutexesMutex.Lock()
+	defer lockMutexesMutex.Unlock()
+	mu, ok := lockMutexes[lockPath]
+	if !ok {
+		mu = &sync.Mutex{}
+		lockMutexes[lockPath] = mu
+	}
+	return mu
+}
+
+func (l *configLock) unlock() {
+	_ = unlockFile(l.file)
+	l.file.Close()
+	l.mu.Unlock()
+}
@@ -0,0 +1,137 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLockConfigDirExcludesGoroutines(t *testing.T) {
+	t.Setenv(configFileEnv, "")
+	configDir := t.TempDir()
+
+	unlock, err := LockConfigDir(configDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	acquired := false
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		unlock, err := LockConfigDir(configDir)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		mu.Lock()
+		acquired = true
+		mu.Unlock()
+		unlock()
+	}()
+
+	time.Sleep(10 * lockRetryInterval)
+	mu.Lock()
+	if acquired {
+		t.Error("a second goroutine took the lock while it was held")
+	}
+	mu.Unlock()
+	unlock()
+
+	select {
+	case <-done:
+	case <-time.After(lockTimeout):
+		t.Fatal("the lock was not taken after being released")
+	}
+	if !acquired {
+		t.Error("the second goroutine never took the lock")
+	}
+}
+
+func TestHeldLockIsNotTakenAgain(t *testing.T) {
+	t.Setenv(configFileEnv, "")
+	configDir := t.TempDir()
+
+	lock, unlock, err := lockConfigDir(nil, configDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+
+	// This would wait for the lock to be released if it was taken again
+	if err := saveBaseConfiguration(lock, configDir, BaseConfigFile{CurrentContext: "dev"}); err != nil {
+		t.Fatal(err)
+	}
+	base, err := LoadBaseConfiguration(configDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if base.CurrentContext != "dev" {
+		t.Errorf("current context = %q, want dev", base.CurrentContext)
+	}
+}
+
+func TestWriteFilesAtomicallyRollsBack(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing.yaml")
+	if err := os.WriteFile(existing, []byte("old"), secretFileMode); err != nil {
+		t.Fatal(err)
+	}
+	created := filepath.Join(dir, "created.yaml")
+	// Renaming a file over a non-empty directory fails
+	blocked := filepath.Join(dir, "blocked")
+	if err := os.MkdirAll(filepath.Join(blocked, "child"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	err := writeFilesAtomically([]pendingFile{
+		{name: existing, contents: []byte("new"), mode: configFileMode},
+		{name: created, contents: []byte("new"), mode: configFileMode},
+		{name: blocked, contents: []byte("new"), mode: configFileMode},
+	})
+	if err == nil {
+		t.Fatal("writeFilesAtomically succeeded, want an error")
+	}
+
+	contents, err := os.ReadFile(existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(contents) != "old" {
+		t.Errorf("existing file contents = %q, want \"old\"", contents)
+	}
+	info, err := os.Stat(existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != secretFileMode {
+		t.Errorf("existing file mode = %v, want %v", info.Mode().Perm(), secretFileMode)
+	}
+	if _, err := os.Stat(created); !os.IsNotExist(err) {
+		t.Errorf("created file was not removed: %v", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("%d entries left in the directory, want 2 (no temporary file)", len(entries))
+	}
+}
@@ -25,10 +25,23 @@ import (
 	"github.com/spf13/viper"
 )
 
+// currentContextName is the context loaded by ConfigureViper
+var currentContextName string
+
+// CurrentContextName returns the name of the context loaded in Viper, taking overrides into account.
+// It returns an empty string when no context has been loaded.
+func CurrentContextName() string {
+	return currentContextName
+}
+
 // ConfigureViper sets up Viper to behave correctly with regards to both context and
 // configuration directory, taking into account all environment variables and parameters.
 // Order of precedence is: override, environment variables, defaults
 func ConfigureViper(contextOverride string) error {
+	if files := GetConfigFiles(); len(files) > 0 {
+		return configureViperFromFiles(files, contextOverride)
+	}
+
 	// Get configuration directory, first of all
 	configDir := GetConfigDir()
 	// Check if it exists
@@ -51,16 +64,11 @@ func ConfigureViper(contextOverride string) error {
 		currentContext = currentContextInterface.(string)
 	}
 
-	// Check overrides
-	if contextOverride != "" {
-		currentContext = contextOverride
-	} else if contextFromEnv, ok := os.LookupEnv("ASTARTE_CONTEXT"); ok && contextFromEnv != "" {
-		currentContext = contextFromEnv
-	}
-
-	if currentContext == "" {
-		return errors.New("No current context defined")
+	currentContext, err := resolveCurrentContext(currentContext, contextOverride)
+	if err != nil {
+		return err
 	}
+	currentContextName = currentContext
 
 	// Load the current context
 	contextViper := viper.New()
@@ -91,6 +99,21 @@ func ConfigureViper(contextOverride string) error {
 	return viper.MergeConfigMap(clusterViper.AllSettings())
 }
 
+// resolveCurrentContext returns the context to use, taking overrides into account
+func resolveCurrentContext(currentContext, contextOverride string) (string, error) {
+	// Check overrides
+	if contextOverride != "" {
+		currentContext = contextOverride
+	} else if contextFromEnv, ok := os.LookupEnv("ASTARTE_CONTEXT"); ok && contextFromEnv != "" {
+		currentContext = contextFromEnv
+	}
+
+	if currentContext == "" {
+		return "", errors.New("No current context defined")
+	}
+	return currentContext, nil
+}
+
 // GetConfigDir returns the Config Dir based on the current status
 func GetConfigDir() string {
 	configDir := GetDefaultConfigDir()
@@ -109,6 +132,11 @@ func GetDefaultConfigDir() string {
 	return configdir.New("", "astarte").QueryFolders(configdir.Global)[0].Path
 }
 
+// GetCacheDir returns the directory where astartectl caches data, such as short lived credentials
+func GetCacheDir() string {
+	return path.Join(GetConfigDir(), "cache")
+}
+
 func clustersDirFromConfigDir(configDir string) string {
 	if configDir == "" {
 		configDir = GetConfigDir()
@@ -213,6 +241,13 @@ func GetBaseConfig(configDir string) BaseConfigFile {
 	return baseConfig
 }
 func UpdateBaseConfigWithContext(configDir, context string) BaseConfigFile {
+	lock, unlock, err := lockConfigDir(nil, configDir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer unlock()
+
 	baseConfig, err := LoadBaseConfiguration(configDir)
 	if err != nil {
 		// Shoot out a warning, but don't fail
@@ -223,7 +258,7 @@ func UpdateBaseConfigWithContext(configDir, context string) BaseConfigFile {
 
 	baseConfig.CurrentContext = context
 
-	if err := SaveBaseConfiguration(configDir, baseConfig); err != nil {
+	if err := saveBaseConfiguration(lock, configDir, baseConfig); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
@@ -0,0 +1,93 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interfacediff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/astarte-platform/astarte-go/interfaces"
+)
+
+// CompatibilityError explains why an interface can't be updated in place to a new definition
+type CompatibilityError struct {
+	// Name is the name of the interface
+	Name string
+	// Major is the major version being updated
+	Major int
+	// OldMinor and NewMinor are the minor versions of the two definitions
+	OldMinor, NewMinor int
+	// Reasons are the rules broken by the new definition
+	Reasons []string
+	// MajorBumpRequired is true when the new definition can only be installed as a new major version
+	MajorBumpRequired bool
+}
+
+func (e *CompatibilityError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s v%d can't be updated from %d.%d to %d.%d:", e.Name, e.Major, e.Major, e.OldMinor, e.Major, e.NewMinor)
+	for _, r := range e.Reasons {
+		fmt.Fprintf(&b, "\n  - %s", r)
+	}
+	if e.MajorBumpRequired {
+		fmt.Fprintf(&b, "\nThese changes require a new major version: set version_major to %d and install the interface instead.", e.Major+1)
+	}
+	return b.String()
+}
+
+// CheckCompatibility checks that newInterface is a valid update of oldInterface within the same
+// major version, following the evolution rules of Astarte: the minor version must increase,
+// type, ownership and aggregation can't change, and mappings can be added but not removed or
+// retyped. It returns a *CompatibilityError when newInterface breaks any rule, and an error when
+// the two definitions aren't of the same interface major version.
+func CheckCompatibility(oldInterface, newInterface interfaces.AstarteInterface) error {
+	if oldInterface.Name != newInterface.Name {
+		return fmt.Errorf("%s and %s are different interfaces", oldInterface.Name, newInterface.Name)
+	}
+	if oldInterface.MajorVersion != newInterface.MajorVersion {
+		return fmt.Errorf("%s has different major versions (%d and %d), which are independent interfaces: install v%d instead of updating it",
+			newInterface.Name, oldInterface.MajorVersion, newInterface.MajorVersion, newInterface.MajorVersion)
+	}
+
+	e := &CompatibilityError{
+		Name:     newInterface.Name,
+		Major:    newInterface.MajorVersion,
+		OldMinor: oldInterface.MinorVersion,
+		NewMinor: newInterface.MinorVersion,
+	}
+	if newInterface.MinorVersion <= oldInterface.MinorVersion {
+		e.Reasons = append(e.Reasons, fmt.Sprintf("version_minor must be greater than %d", oldInterface.MinorVersion))
+	}
+	for _, c := range Compare(oldInterface, newInterface) {
+		switch {
+		case c.Kind == KindRemoved:
+			e.Reasons = append(e.Reasons, fmt.Sprintf("mapping %s is removed", c.Endpoint))
+			e.MajorBumpRequired = true
+		case c.Kind != KindChanged:
+			continue
+		case c.Endpoint != "" && c.Field == "type":
+			e.Reasons = append(e.Reasons, fmt.Sprintf("mapping %s changes type from %s to %s", c.Endpoint, c.Old, c.New))
+			e.MajorBumpRequired = true
+		case c.Endpoint == "" && (c.Field == "type" || c.Field == "ownership" || c.Field == "aggregation"):
+			e.Reasons = append(e.Reasons, fmt.Sprintf("interface %s changes from %s to %s", c.Field, c.Old, c.New))
+			e.MajorBumpRequired = true
+		}
+	}
+
+	if len(e.Reasons) > 0 {
+		return e
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interfacediff
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/astarte-platform/astarte-go/interfaces"
+)
+
+func TestCheckCompatibility(t *testing.T) {
+	value := mapping("/%{sensor}/value", interfaces.Double)
+	name := mapping("/%{sensor}/name", interfaces.String)
+	documented := value
+	documented.Documentation = "The value of the sensor"
+	serverOwned := testInterface(1, value)
+	serverOwned.Ownership = interfaces.ServerOwnership
+	properties := testInterface(1, value)
+	properties.Type = interfaces.PropertiesType
+
+	tests := []struct {
+		name       string
+		old, new   interfaces.AstarteInterface
+		reasons    []string
+		majorBump  bool
+		otherError bool
+	}{
+		{
+			name: "added mapping",
+			old:  testInterface(0, value),
+			new:  testInterface(1, value, name),
+		},
+		{
+			name: "documentation",
+			old:  testInterface(0, value),
+			new:  testInterface(1, documented),
+		},
+		{
+			name:    "same minor",
+			old:     testInterface(1, value),
+			new:     testInterface(1, value, name),
+			reasons: []string{"version_minor must be greater than 1"},
+		},
+		{
+			name:    "lower minor",
+			old:     testInterface(2, value),
+			new:     testInterface(1, value),
+			reasons: []string{"version_minor must be greater than 2"},
+		},
+		{
+			name:      "removed mapping",
+			old:       testInterface(0, value, name),
+			new:       testInterface(1, value),
+			reasons:   []string{"mapping /%{sensor}/name is removed"},
+			majorBump: true,
+		},
+		{
+			name:      "retyped mapping",
+			old:       testInterface(0, value),
+			new:       testInterface(1, mapping("/%{sensor}/value", interfaces.Integer)),
+			reasons:   []string{"mapping /%{sensor}/value changes type from double to integer"},
+			majorBump: true,
+		},
+		{
+			name:      "ownership",
+			old:       testInterface(0, value),
+			new:       serverOwned,
+			reasons:   []string{"interface ownership changes from device to server"},
+			majorBump: true,
+		},
+		{
+			name:      "type",
+			old:       testInterface(0, value),
+			new:       properties,
+			reasons:   []string{"interface type changes from datastream to properties"},
+			majorBump: true,
+		},
+		{
+			name: "several",
+			old:  testInterface(1, value, name),
+			new:  testInterface(1, mapping("/%{sensor}/value", interfaces.Integer)),
+			reasons: []string{
+				"version_minor must be greater than 1",
+				"mapping /%{sensor}/name is removed",
+				"mapping /%{sensor}/value changes type from double to integer",
+			},
+			majorBump: true,
+		},
+		{
+			name:       "different major",
+			old:        testInterface(0, value),
+			new:        interfaces.AstarteInterface{Name: "org.astarte-platform.Test", MajorVersion: 2},
+			otherError: true,
+		},
+		{
+			name:       "different name",
+			old:        testInterface(0, value),
+			new:        interfaces.AstarteInterface{Name: "org.astarte-platform.Other", MajorVersion: 1},
+			otherError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckCompatibility(tt.old, tt.new)
+			var compatErr *CompatibilityError
+			switch {
+			case tt.otherError:
+				if err == nil || errors.As(err, &compatErr) {
+					t.Fatalf("CheckCompatibility() = %v, want a generic error", err)
+				}
+			case tt.reasons == nil:
+				if err != nil {
+					t.Fatalf("CheckCompatibility() = %v, want nil", err)
+				}
+			default:
+				if !errors.As(err, &compatErr) {
+					t.Fatalf("CheckCompatibility() = %v, want a *CompatibilityError", err)
+				}
+				if !reflect.DeepEqual(compatErr.Reasons, tt.reasons) {
+					t.Errorf("Reasons = %q, want %q", compatErr.Reasons, tt.reasons)
+				}
+				if compatErr.MajorBumpRequired != tt.majorBump {
+					t.Errorf("MajorBumpRequired = %v, want %v", compatErr.MajorBumpRequired, tt.majorBump)
+				}
+			}
+		})
+	}
+}
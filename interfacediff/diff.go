@@ -0,0 +1,167 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package interfacediff compares two definitions of an Astarte interface field by field and
+// mapping by mapping.
+package interfacediff
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+
+	"github.com/astarte-platform/astarte-go/interfaces"
+)
+
+// Kind is the kind of a Change
+type Kind string
+
+const (
+	// KindAdded is a mapping only present in the new definition
+	KindAdded Kind = "added"
+	// KindRemoved is a mapping only present in the old definition
+	KindRemoved Kind = "removed"
+	// KindChanged is a field with a different value in the two definitions
+	KindChanged Kind = "changed"
+)
+
+// Change is a single difference between two definitions of an interface. Endpoint is empty for
+// changes to the interface itself.
+type Change struct {
+	Kind     Kind        `json:"kind"`
+	Endpoint string      `json:"endpoint,omitempty"`
+	Field    string      `json:"field,omitempty"`
+	Old      interface{} `json:"old,omitempty"`
+	New      interface{} `json:"new,omitempty"`
+}
+
+// String returns a human readable description of the change
+func (c Change) String() string {
+	switch c.Kind {
+	case KindAdded:
+		return fmt.Sprintf("+ %s (%v)", c.Endpoint, c.New)
+	case KindRemoved:
+		return fmt.Sprintf("- %s (%v)", c.Endpoint, c.Old)
+	}
+	if c.Endpoint == "" {
+		return fmt.Sprintf("~ %s: %s -> %s", c.Field, formatValue(c.Old), formatValue(c.New))
+	}
+	return fmt.Sprintf("~ %s: %s %s -> %s", c.Endpoint, c.Field, formatValue(c.Old), formatValue(c.New))
+}
+
+// field is a field compared between two definitions, named after its JSON key
+type field struct {
+	name  string
+	value func(v reflect.Value) interface{}
+}
+
+func structField(name string) func(v reflect.Value) interface{} {
+	return func(v reflect.Value) interface{} {
+		return v.FieldByName(name).Interface()
+	}
+}
+
+var interfaceFields = []field{
+	{"version_major", structField("MajorVersion")},
+	{"version_minor", structField("MinorVersion")},
+	{"type", structField("Type")},
+	{"ownership", structField("Ownership")},
+	{"aggregation", structField("Aggregation")},
+	{"explicit_timestamp", structField("ExplicitTimestamp")},
+	{"has_metadata", structField("HasMetadata")},
+	{"description", structField("Description")},
+	{"doc", structField("Documentation")},
+}
+
+var mappingFields = []field{
+	{"endpoint", structField("Endpoint")},
+	{"type", structField("Type")},
+	{"reliability", structField("Reliability")},
+	{"retention", structField("Retention")},
+	{"expiry", structField("Expiry")},
+	{"database_retention_policy", structField("DatabaseRetentionPolicy")},
+	{"database_retention_ttl", structField("DatabaseRetentionTTL")},
+	{"explicit_timestamp", structField("ExplicitTimestamp")},
+	{"allow_unset", structField("AllowUnset")},
+	{"description", structField("Description")},
+	{"doc", structField("Documentation")},
+}
+
+// Compare returns the changes needed to turn oldInterface into newInterface. Defaults are applied
+// to both before comparing them, and mappings are matched by endpoint, regardless of the names of
+// their parameters: renaming a parameter is reported as a change of the endpoint.
+func Compare(oldInterface, newInterface interfaces.AstarteInterface) []Change {
+	oldInterface = interfaces.EnsureInterfaceDefaults(oldInterface)
+	newInterface = interfaces.EnsureInterfaceDefaults(newInterface)
+
+	changes := compareFields(interfaceFields, "", reflect.ValueOf(oldInterface), reflect.ValueOf(newInterface))
+
+	oldMappings := mappingsByEndpoint(oldInterface.Mappings)
+	newMappings := mappingsByEndpoint(newInterface.Mappings)
+	endpoints := []string{}
+	for e := range oldMappings {
+		endpoints = append(endpoints, e)
+	}
+	for e := range newMappings {
+		if _, ok := oldMappings[e]; !ok {
+			endpoints = append(endpoints, e)
+		}
+	}
+	sort.Strings(endpoints)
+
+	for _, e := range endpoints {
+		oldMapping, inOld := oldMappings[e]
+		newMapping, inNew := newMappings[e]
+		switch {
+		case !inOld:
+			changes = append(changes, Change{Kind: KindAdded, Endpoint: newMapping.Endpoint, New: newMapping.Type})
+		case !inNew:
+			changes = append(changes, Change{Kind: KindRemoved, Endpoint: oldMapping.Endpoint, Old: oldMapping.Type})
+		default:
+			changes = append(changes, compareFields(mappingFields, newMapping.Endpoint, reflect.ValueOf(oldMapping), reflect.ValueOf(newMapping))...)
+		}
+	}
+	return changes
+}
+
+func compareFields(fields []field, endpoint string, oldValue, newValue reflect.Value) []Change {
+	changes := []Change{}
+	for _, f := range fields {
+		o, n := f.value(oldValue), f.value(newValue)
+		if o != n {
+			changes = append(changes, Change{Kind: KindChanged, Endpoint: endpoint, Field: f.name, Old: o, New: n})
+		}
+	}
+	return changes
+}
+
+var parameterRegexp = regexp.MustCompile(`%{[^}]*}`)
+
+// mappingsByEndpoint indexes mappings by their endpoint, with the names of parameters stripped
+func mappingsByEndpoint(mappings []interfaces.AstarteInterfaceMapping) map[string]interfaces.AstarteInterfaceMapping {
+	ret := map[string]interfaces.AstarteInterfaceMapping{}
+	for _, m := range mappings {
+		ret[parameterRegexp.ReplaceAllString(m.Endpoint, "%{}")] = m
+	}
+	return ret
+}
+
+func formatValue(v interface{}) string {
+	// Plain strings are free text, such as descriptions, while enums are printed as they are
+	if s, ok := v.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	return fmt.Sprint(v)
+}
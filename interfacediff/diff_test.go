@@ -0,0 +1,154 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interfacediff
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/astarte-platform/astarte-go/interfaces"
+)
+
+func testInterface(minor int, mappings ...interfaces.AstarteInterfaceMapping) interfaces.AstarteInterface {
+	return interfaces.AstarteInterface{
+		Name:         "org.astarte-platform.Test",
+		MajorVersion: 1,
+		MinorVersion: minor,
+		Type:         interfaces.DatastreamType,
+		Ownership:    interfaces.DeviceOwnership,
+		Mappings:     mappings,
+	}
+}
+
+func mapping(endpoint string, mappingType interfaces.AstarteMappingType) interfaces.AstarteInterfaceMapping {
+	return interfaces.AstarteInterfaceMapping{Endpoint: endpoint, Type: mappingType}
+}
+
+func TestCompare(t *testing.T) {
+	value := mapping("/%{sensor}/value", interfaces.Double)
+	guaranteed := value
+	guaranteed.Reliability = interfaces.GuaranteedReliability
+	explicit := value
+	explicit.ExplicitTimestamp = true
+	described := value
+	described.Description = "The value"
+	renamed := mapping("/%{id}/value", interfaces.Double)
+	defaulted := value
+	defaulted.Reliability = interfaces.UnreliableReliability
+	defaulted.Retention = interfaces.DiscardRetention
+
+	tests := []struct {
+		name     string
+		old, new interfaces.AstarteInterface
+		want     []Change
+	}{
+		{
+			name: "identical",
+			old:  testInterface(0, value),
+			new:  testInterface(0, value),
+			want: []Change{},
+		},
+		{
+			name: "explicit defaults",
+			old:  testInterface(0, value),
+			new:  testInterface(0, defaulted),
+			want: []Change{},
+		},
+		{
+			name: "added mapping",
+			old:  testInterface(0, value),
+			new:  testInterface(1, value, mapping("/%{sensor}/name", interfaces.String)),
+			want: []Change{
+				{Kind: KindChanged, Field: "version_minor", Old: 0, New: 1},
+				{Kind: KindAdded, Endpoint: "/%{sensor}/name", New: interfaces.String},
+			},
+		},
+		{
+			name: "removed mapping",
+			old:  testInterface(0, value, mapping("/%{sensor}/name", interfaces.String)),
+			new:  testInterface(0, value),
+			want: []Change{
+				{Kind: KindRemoved, Endpoint: "/%{sensor}/name", Old: interfaces.String},
+			},
+		},
+		{
+			name: "retyped mapping",
+			old:  testInterface(0, value),
+			new:  testInterface(0, mapping("/%{sensor}/value", interfaces.Integer)),
+			want: []Change{
+				{Kind: KindChanged, Endpoint: "/%{sensor}/value", Field: "type", Old: interfaces.Double, New: interfaces.Integer},
+			},
+		},
+		{
+			name: "mapping fields",
+			old:  testInterface(0, value),
+			new:  testInterface(0, guaranteed),
+			want: []Change{
+				{Kind: KindChanged, Endpoint: "/%{sensor}/value", Field: "reliability", Old: interfaces.UnreliableReliability, New: interfaces.GuaranteedReliability},
+			},
+		},
+		{
+			name: "explicit timestamp",
+			old:  testInterface(0, value),
+			new:  testInterface(0, explicit),
+			want: []Change{
+				{Kind: KindChanged, Endpoint: "/%{sensor}/value", Field: "explicit_timestamp", Old: false, New: true},
+			},
+		},
+		{
+			name: "description",
+			old:  testInterface(0, value),
+			new:  testInterface(0, described),
+			want: []Change{
+				{Kind: KindChanged, Endpoint: "/%{sensor}/value", Field: "description", Old: "", New: "The value"},
+			},
+		},
+		{
+			name: "renamed parameter",
+			old:  testInterface(0, value),
+			new:  testInterface(0, renamed),
+			want: []Change{
+				{Kind: KindChanged, Endpoint: "/%{id}/value", Field: "endpoint", Old: "/%{sensor}/value", New: "/%{id}/value"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Compare(tt.old, tt.new)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Compare() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChangeString(t *testing.T) {
+	tests := []struct {
+		change Change
+		want   string
+	}{
+		{Change{Kind: KindAdded, Endpoint: "/a", New: interfaces.String}, "+ /a (string)"},
+		{Change{Kind: KindRemoved, Endpoint: "/a", Old: interfaces.String}, "- /a (string)"},
+		{Change{Kind: KindChanged, Field: "version_minor", Old: 0, New: 1}, "~ version_minor: 0 -> 1"},
+		{Change{Kind: KindChanged, Endpoint: "/a", Field: "description", Old: "", New: "A"}, `~ /a: description "" -> "A"`},
+	}
+
+	for _, tt := range tests {
+		if got := tt.change.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
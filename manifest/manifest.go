@@ -0,0 +1,277 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package manifest describes the desired state of a realm through manifest files, and applies it
+// to a live realm.
+//
+// A manifest is a YAML or JSON file, possibly made of multiple documents. Each document is either
+// an envelope such as
+//
+//	kind: Interface
+//	spec:
+//	  interface_name: org.astarte-platform.Example
+//	  ...
+//
+// or a bare interface, trigger or trigger delivery policy, as accepted by the Realm Management API.
+// Supported kinds are Interface, Trigger, TriggerDeliveryPolicy and Group. Groups are described by
+// a spec such as {"group_name": "mygroup", "devices": ["<device id>", ...]}.
+package manifest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/astarte-platform/astarte-go/interfaces"
+	"github.com/astarte-platform/astarte-go/triggers"
+	"gopkg.in/yaml.v3"
+)
+
+// Kind is the kind of a resource described in a manifest
+type Kind string
+
+const (
+	// KindInterface is an Astarte Interface
+	KindInterface Kind = "Interface"
+	// KindTrigger is an Astarte Trigger
+	KindTrigger Kind = "Trigger"
+	// KindTriggerDeliveryPolicy is an Astarte Trigger Delivery Policy
+	KindTriggerDeliveryPolicy Kind = "TriggerDeliveryPolicy"
+	// KindGroup is a group of devices
+	KindGroup Kind = "Group"
+)
+
+// Resource is a single resource described in a manifest
+type Resource struct {
+	// Kind is the kind of the resource
+	Kind Kind
+	// Name identifies the resource among the ones of the same Kind
+	Name string
+	// Source is the file and document the resource was read from
+	Source string
+	// Spec is the JSON representation of the resource, as accepted by Astarte APIs
+	Spec json.RawMessage
+}
+
+// GroupSpec is the spec of a Group resource
+type GroupSpec struct {
+	// Name is the name of the group
+	Name string `json:"group_name"`
+	// Devices are the IDs of the devices belonging to the group
+	Devices []string `json:"devices"`
+}
+
+// Interface returns the interface described by r
+func (r Resource) Interface() (interfaces.AstarteInterface, error) {
+	return interfaces.ParseInterface(r.Spec)
+}
+
+// Trigger returns the trigger described by r
+func (r Resource) Trigger() (triggers.AstarteTrigger, error) {
+	return triggers.ParseTrigger(r.Spec)
+}
+
+// Group returns the group described by r
+func (r Resource) Group() (GroupSpec, error) {
+	group := GroupSpec{}
+	err := json.Unmarshal(r.Spec, &group)
+	return group, err
+}
+
+// RawSpec returns the spec of r as a generic map, preserving fields not known to astartectl
+func (r Resource) RawSpec() (map[string]interface{}, error) {
+	raw := map[string]interface{}{}
+	err := json.Unmarshal(r.Spec, &raw)
+	return raw, err
+}
+
+// LoadPaths reads all resources from the given paths. Directories are walked recursively, loading
+// all .yaml, .yml and .json files in lexical order. "-" reads from stdin.
+func LoadPaths(paths []string) ([]Resource, error) {
+	resources := []Resource{}
+	for _, p := range paths {
+		files := []string{p}
+		if p != "-" {
+			var err error
+			if files, err = manifestFiles(p); err != nil {
+				return nil, err
+			}
+		}
+		for _, f := range files {
+			r, err := loadFile(f)
+			if err != nil {
+				return nil, err
+			}
+			resources = append(resources, r...)
+		}
+	}
+
+	// Astarte would refuse duplicates anyway, but reporting them here is a lot clearer
+	seen := map[string]string{}
+	for _, r := range resources {
+		key := string(r.Kind) + "/" + r.Name
+		if other, ok := seen[key]; ok {
+			return nil, fmt.Errorf("%s %s is defined both in %s and %s", r.Kind, r.Name, other, r.Source)
+		}
+		seen[key] = r.Source
+	}
+	return resources, nil
+}
+
+func manifestFiles(p string) ([]string, error) {
+	info, err := os.Stat(p)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{p}, nil
+	}
+
+	files := []string{}
+	err = filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml", ".json":
+			if !d.IsDir() {
+				files = append(files, path)
+			}
+		}
+		return nil
+	})
+	sort.Strings(files)
+	return files, err
+}
+
+func loadFile(fileName string) ([]Resource, error) {
+	var reader io.Reader
+	if fileName == "-" {
+		reader = os.Stdin
+	} else {
+		f, err := os.Open(fileName)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		reader = f
+	}
+
+	resources := []Resource{}
+	// YAML 1.2 is needed, since YAML 1.1 would turn the "on" keys of triggers and policies into booleans.
+	// JSON documents are valid YAML too.
+	decoder := yaml.NewDecoder(reader)
+	for i := 1; ; i++ {
+		document := map[string]interface{}{}
+		if err := decoder.Decode(&document); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("%s: %w", fileName, err)
+		}
+		if len(document) == 0 {
+			// Empty document, e.g. a trailing ---
+			continue
+		}
+
+		source := fmt.Sprintf("%s#%d", fileName, i)
+		r, err := resourceFromDocument(document)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", source, err)
+		}
+		r.Source = source
+		resources = append(resources, r)
+	}
+	return resources, nil
+}
+
+func resourceFromDocument(document map[string]interface{}) (Resource, error) {
+	r := Resource{}
+	spec := document
+	if kind, ok := document["kind"].(string); ok {
+		r.Kind = Kind(kind)
+		if spec, ok = document["spec"].(map[string]interface{}); !ok {
+			return r, errors.New("spec is missing")
+		}
+	} else {
+		r.Kind = guessKind(document)
+	}
+
+	var err error
+	if r.Spec, err = json.Marshal(spec); err != nil {
+		return r, err
+	}
+
+	switch r.Kind {
+	case KindInterface:
+		iface, err := r.Interface()
+		if err != nil {
+			return r, err
+		}
+		r.Name = InterfaceResourceName(iface.Name, iface.MajorVersion)
+	case KindTrigger:
+		trigger, err := r.Trigger()
+		if err != nil {
+			return r, err
+		}
+		r.Name = trigger.Name
+	case KindTriggerDeliveryPolicy:
+		name, _ := spec["name"].(string)
+		if name == "" {
+			return r, errors.New("trigger delivery policy has no name")
+		}
+		r.Name = name
+	case KindGroup:
+		group, err := r.Group()
+		if err != nil {
+			return r, err
+		}
+		if group.Name == "" {
+			return r, errors.New("group has no group_name")
+		}
+		r.Name = group.Name
+	case "":
+		return r, errors.New("could not determine the kind of the document, please specify it")
+	default:
+		return r, fmt.Errorf("unsupported kind %s", r.Kind)
+	}
+	return r, nil
+}
+
+// guessKind determines the kind of a bare document through the fields which are peculiar to each kind
+func guessKind(document map[string]interface{}) Kind {
+	switch {
+	case document["interface_name"] != nil && document["mappings"] != nil:
+		return KindInterface
+	case document["simple_triggers"] != nil:
+		return KindTrigger
+	case document["error_handlers"] != nil:
+		return KindTriggerDeliveryPolicy
+	case document["group_name"] != nil:
+		return KindGroup
+	}
+	return ""
+}
+
+// InterfaceResourceName is the name of an interface resource, since multiple major versions of the
+// same interface can coexist
+func InterfaceResourceName(name string, major int) string {
+	return fmt.Sprintf("%s v%d", name, major)
+}
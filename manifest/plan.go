@@ -0,0 +1,488 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/astarte-platform/astarte-go/client"
+	"github.com/astarte-platform/astarte-go/interfaces"
+	"github.com/astarte-platform/astarte-go/triggers"
+)
+
+// Action is what applying a Change does to the realm
+type Action string
+
+const (
+	// ActionCreate creates a resource missing from the realm
+	ActionCreate Action = "create"
+	// ActionUpdate updates a resource in place
+	ActionUpdate Action = "update"
+	// ActionReplace deletes a resource and creates it again, as it can't be updated in place
+	ActionReplace Action = "replace"
+	// ActionUnchanged means the realm already matches the manifest
+	ActionUnchanged Action = "unchanged"
+	// ActionSkip means the resource differs from the realm, but it can't be reconciled
+	ActionSkip Action = "skip"
+)
+
+// kindOrder is the order in which kinds are applied, so that resources are created after the
+// ones they depend on: triggers refer to policies and interfaces.
+var kindOrder = map[Kind]int{
+	KindTriggerDeliveryPolicy: 0,
+	KindInterface:             1,
+	KindTrigger:               2,
+	KindGroup:                 3,
+}
+
+// Change is a single step of a Plan
+type Change struct {
+	Action  Action `json:"action"`
+	Kind    Kind   `json:"kind"`
+	Name    string `json:"name"`
+	Details string `json:"details,omitempty"`
+	Source  string `json:"source"`
+
+	resource     Resource
+	devicesToAdd []string
+}
+
+// Plan is the ordered list of changes needed to bring a realm to the state described by a manifest
+type Plan struct {
+	Realm   string   `json:"realm"`
+	Changes []Change `json:"changes"`
+}
+
+// Options tweak how a Plan is computed
+type Options struct {
+	// Force allows replacing triggers and trigger delivery policies which differ from the manifest.
+	// Replacing a resource deletes it first, so it is not done by default.
+	Force bool
+}
+
+// HasChanges returns true if applying p would modify the realm
+func (p Plan) HasChanges() bool {
+	for _, c := range p.Changes {
+		switch c.Action {
+		case ActionCreate, ActionUpdate, ActionReplace:
+			return true
+		}
+	}
+	return false
+}
+
+// liveState caches what is currently installed in the realm
+type liveState struct {
+	c     *client.Client
+	realm string
+
+	interfaceNames map[string]bool
+	triggerNames   map[string]bool
+	policyNames    map[string]bool
+	groupNames     map[string]bool
+}
+
+// NewPlan compares resources with the live realm, and returns the changes needed to reconcile them.
+// Changes are sorted in dependency order.
+func NewPlan(c *client.Client, realm string, resources []Resource, opts Options) (Plan, error) {
+	plan := Plan{Realm: realm, Changes: []Change{}}
+	state := &liveState{c: c, realm: realm}
+	if err := state.load(resources); err != nil {
+		return plan, err
+	}
+
+	// Resources defined in the manifest satisfy dependencies, as they are applied first
+	manifestPolicies := map[string]bool{}
+	manifestInterfaces := map[string]bool{}
+	for _, r := range resources {
+		switch r.Kind {
+		case KindTriggerDeliveryPolicy:
+			manifestPolicies[r.Name] = true
+		case KindInterface:
+			iface, _ := r.Interface()
+			manifestInterfaces[iface.Name] = true
+		}
+	}
+
+	sorted := append([]Resource{}, resources...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Kind != sorted[j].Kind {
+			return kindOrder[sorted[i].Kind] < kindOrder[sorted[j].Kind]
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	for _, r := range sorted {
+		change := Change{Kind: r.Kind, Name: r.Name, Source: r.Source, resource: r}
+		var err error
+		switch r.Kind {
+		case KindTriggerDeliveryPolicy:
+			err = state.planPolicy(&change, opts)
+		case KindInterface:
+			err = state.planInterface(&change)
+		case KindTrigger:
+			err = state.planTrigger(&change, opts, manifestPolicies, manifestInterfaces)
+		case KindGroup:
+			err = state.planGroup(&change)
+		}
+		if err != nil {
+			return plan, fmt.Errorf("%s %s: %w", r.Kind, r.Name, err)
+		}
+		plan.Changes = append(plan.Changes, change)
+	}
+
+	return plan, nil
+}
+
+// Apply performs all changes in plan, in order. It stops at the first failure, as later changes
+// might depend on the failed one. Progress is reported to out.
+func Apply(c *client.Client, plan Plan, out io.Writer) error {
+	for _, change := range plan.Changes {
+		var err error
+		switch change.Action {
+		case ActionCreate, ActionUpdate, ActionReplace:
+			err = applyChange(c, plan.Realm, change)
+		default:
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("could not %s %s %s: %w", change.Action, change.Kind, change.Name, err)
+		}
+		fmt.Fprintf(out, "%s %s: %s done\n", change.Kind, change.Name, change.Action)
+	}
+	return nil
+}
+
+func applyChange(c *client.Client, realm string, change Change) error {
+	r := change.resource
+	switch r.Kind {
+	case KindTriggerDeliveryPolicy:
+		if change.Action == ActionReplace {
+			if err := runRequest(c)(c.DeleteTriggerDeliveryPolicy(realm, r.Name)); err != nil {
+				return err
+			}
+		}
+		raw, err := r.RawSpec()
+		if err != nil {
+			return err
+		}
+		return runRequest(c)(c.InstallTriggerDeliveryPolicy(realm, raw))
+
+	case KindInterface:
+		iface, err := r.Interface()
+		if err != nil {
+			return err
+		}
+		if change.Action == ActionUpdate {
+			return runRequest(c)(c.UpdateInterface(realm, iface.Name, iface.MajorVersion, iface, false))
+		}
+		return runRequest(c)(c.InstallInterface(realm, iface, false))
+
+	case KindTrigger:
+		if change.Action == ActionReplace {
+			if err := runRequest(c)(c.DeleteTrigger(realm, r.Name)); err != nil {
+				return err
+			}
+		}
+		// Install the raw spec, so that fields unknown to astartectl (e.g. policy) are preserved
+		raw, err := r.RawSpec()
+		if err != nil {
+			return err
+		}
+		return runRequest(c)(c.InstallTrigger(realm, raw))
+
+	case KindGroup:
+		if change.Action == ActionCreate {
+			group, err := r.Group()
+			if err != nil {
+				return err
+			}
+			return runRequest(c)(c.CreateGroup(realm, group.Name, group.Devices))
+		}
+		for _, d := range change.devicesToAdd {
+			if err := runRequest(c)(c.AddDeviceToGroup(realm, r.Name, d)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *liveState) load(resources []Resource) error {
+	kinds := map[Kind]bool{}
+	for _, r := range resources {
+		kinds[r.Kind] = true
+	}
+
+	var err error
+	// Triggers are checked against existing interfaces and policies too
+	if kinds[KindInterface] || kinds[KindTrigger] {
+		if s.interfaceNames, err = listNames(s.c)(s.c.ListInterfaces(s.realm)); err != nil {
+			return err
+		}
+	}
+	if kinds[KindTriggerDeliveryPolicy] || kinds[KindTrigger] {
+		if s.policyNames, err = listNames(s.c)(s.c.ListTriggerDeliveryPolicies(s.realm)); err != nil {
+			return err
+		}
+	}
+	if kinds[KindTrigger] {
+		if s.triggerNames, err = listNames(s.c)(s.c.ListTriggers(s.realm)); err != nil {
+			return err
+		}
+	}
+	if kinds[KindGroup] {
+		if s.groupNames, err = listNames(s.c)(s.c.ListGroups(s.realm)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *liveState) planPolicy(change *Change, opts Options) error {
+	if !s.policyNames[change.Name] {
+		change.Action = ActionCreate
+		return nil
+	}
+
+	live, err := parseRequest(s.c)(s.c.GetTriggerDeliveryPolicy(s.realm, change.Name))
+	if err != nil {
+		return err
+	}
+	local, err := change.resource.RawSpec()
+	if err != nil {
+		return err
+	}
+	livePolicy, _ := live.(map[string]interface{})
+	if fieldsMatch(local, livePolicy) {
+		change.Action = ActionUnchanged
+	} else if opts.Force {
+		change.Action = ActionReplace
+		change.Details = "policies can't be updated, it will be deleted and installed again"
+	} else {
+		change.Action = ActionSkip
+		change.Details = "differs from the realm, use --force to replace it"
+	}
+	return nil
+}
+
+func (s *liveState) planInterface(change *Change) error {
+	local, err := change.resource.Interface()
+	if err != nil {
+		return err
+	}
+	if !s.interfaceNames[local.Name] {
+		change.Action = ActionCreate
+		return nil
+	}
+
+	majors, err := parseRequest(s.c)(s.c.ListInterfaceMajorVersions(s.realm, local.Name))
+	if err != nil {
+		return err
+	}
+	installed := false
+	for _, m := range majors.([]int) {
+		installed = installed || m == local.MajorVersion
+	}
+	if !installed {
+		change.Action = ActionCreate
+		return nil
+	}
+
+	rawLive, err := parseRequest(s.c)(s.c.GetInterface(s.realm, local.Name, local.MajorVersion))
+	if err != nil {
+		return err
+	}
+	live := interfaces.EnsureInterfaceDefaults(rawLive.(interfaces.AstarteInterface))
+
+	switch {
+	case live.MinorVersion < local.MinorVersion:
+		change.Action = ActionUpdate
+		change.Details = fmt.Sprintf("minor version %d -> %d", live.MinorVersion, local.MinorVersion)
+	case live.MinorVersion > local.MinorVersion:
+		change.Action = ActionSkip
+		change.Details = fmt.Sprintf("the realm has a more recent minor version (%d)", live.MinorVersion)
+	case reflect.DeepEqual(live, local):
+		change.Action = ActionUnchanged
+	default:
+		change.Action = ActionSkip
+		change.Details = "differs from the realm but has the same minor version, bump version_minor to update it"
+	}
+	return nil
+}
+
+func (s *liveState) planTrigger(change *Change, opts Options, manifestPolicies, manifestInterfaces map[string]bool) error {
+	raw, err := change.resource.RawSpec()
+	if err != nil {
+		return err
+	}
+	local, err := change.resource.Trigger()
+	if err != nil {
+		return err
+	}
+
+	// Catch missing dependencies before touching the realm
+	if policy, _ := raw["policy"].(string); policy != "" && !manifestPolicies[policy] && !s.policyNames[policy] {
+		change.Action = ActionSkip
+		change.Details = fmt.Sprintf("references trigger delivery policy %s, which does not exist", policy)
+		return nil
+	}
+	for _, st := range local.SimpleTriggers {
+		if st.InterfaceName != "" && st.InterfaceName != "*" && !manifestInterfaces[st.InterfaceName] && !s.interfaceNames[st.InterfaceName] {
+			change.Action = ActionSkip
+			change.Details = fmt.Sprintf("references interface %s, which does not exist", st.InterfaceName)
+			return nil
+		}
+	}
+
+	if !s.triggerNames[change.Name] {
+		change.Action = ActionCreate
+		return nil
+	}
+
+	live, err := parseRequest(s.c)(s.c.GetTrigger(s.realm, change.Name))
+	if err != nil {
+		return err
+	}
+	liveRaw, _ := live.(map[string]interface{})
+	liveNormalized, err := normalizeTrigger(liveRaw)
+	if err != nil {
+		return err
+	}
+	localNormalized, err := normalizeTrigger(raw)
+	if err != nil {
+		return err
+	}
+
+	if liveNormalized == localNormalized {
+		change.Action = ActionUnchanged
+	} else if opts.Force {
+		change.Action = ActionReplace
+		change.Details = "triggers can't be updated, it will be deleted and installed again"
+	} else {
+		change.Action = ActionSkip
+		change.Details = "differs from the realm, use --force to replace it"
+	}
+	return nil
+}
+
+func (s *liveState) planGroup(change *Change) error {
+	group, err := change.resource.Group()
+	if err != nil {
+		return err
+	}
+	if !s.groupNames[group.Name] {
+		change.Action = ActionCreate
+		change.Details = fmt.Sprintf("%d device(s)", len(group.Devices))
+		return nil
+	}
+
+	paginator, err := s.c.ListGroupDevices(s.realm, group.Name, 100, client.DeviceIDFormat)
+	if err != nil {
+		return err
+	}
+	liveDevices := map[string]bool{}
+	for paginator.HasNextPage() {
+		page, err := parseRequest(s.c)(paginator.GetNextPage())
+		if err != nil {
+			return err
+		}
+		devices, _ := page.([]string)
+		for _, d := range devices {
+			liveDevices[d] = true
+		}
+	}
+
+	for _, d := range group.Devices {
+		if !liveDevices[d] {
+			change.devicesToAdd = append(change.devicesToAdd, d)
+		}
+	}
+	if len(change.devicesToAdd) == 0 {
+		change.Action = ActionUnchanged
+	} else {
+		change.Action = ActionUpdate
+		change.Details = fmt.Sprintf("add devices %s", strings.Join(change.devicesToAdd, ", "))
+	}
+	return nil
+}
+
+// normalizeTrigger returns a canonical representation of a trigger, with all defaults set
+func normalizeTrigger(raw map[string]interface{}) (string, error) {
+	marshaled, err := json.Marshal(raw)
+	if err != nil {
+		return "", err
+	}
+	trigger, err := triggers.ParseTrigger(marshaled)
+	if err != nil {
+		return "", err
+	}
+	normalized, err := json.Marshal(struct {
+		Trigger triggers.AstarteTrigger
+		Policy  interface{}
+	}{trigger, raw["policy"]})
+	return string(normalized), err
+}
+
+// fieldsMatch returns true if all fields set in local have the same value in live. Fields which are
+// set only in live are ignored, as they are usually defaults filled by Astarte.
+func fieldsMatch(local, live map[string]interface{}) bool {
+	for k, v := range local {
+		if !reflect.DeepEqual(v, live[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+func runRequest(c *client.Client) func(client.AstarteRequest, error) error {
+	return func(req client.AstarteRequest, err error) error {
+		_, err = parseRequest(c)(req, err)
+		return err
+	}
+}
+
+func parseRequest(c *client.Client) func(client.AstarteRequest, error) (interface{}, error) {
+	return func(req client.AstarteRequest, err error) (interface{}, error) {
+		if err != nil {
+			return nil, err
+		}
+		res, err := req.Run(c)
+		if err != nil {
+			return nil, err
+		}
+		return res.Parse()
+	}
+}
+
+func listNames(c *client.Client) func(client.AstarteRequest, error) (map[string]bool, error) {
+	return func(req client.AstarteRequest, err error) (map[string]bool, error) {
+		raw, err := parseRequest(c)(req, err)
+		if err != nil {
+			return nil, err
+		}
+		names := map[string]bool{}
+		list, _ := raw.([]string)
+		for _, n := range list {
+			names[n] = true
+		}
+		return names, nil
+	}
+}
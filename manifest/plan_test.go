@@ -0,0 +1,224 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/astarte-platform/astarte-go/client"
+	"github.com/astarte-platform/astarte-go/interfaces"
+	"github.com/astarte-platform/astartectl/mockserver"
+)
+
+const (
+	installedInterface = `{"interface_name": "%s", "version_major": 0, "version_minor": %d, "type": "datastream",
+		"ownership": "device", "mappings": [{"endpoint": "/%%{sensor}/value", "type": "%s"}]}`
+	installedPolicy = `{"name": "retry", "error_handlers": [{"on": "any_error", "strategy": "retry"}],
+		"maximum_capacity": 100}`
+	installedTrigger = `{"name": "on_value", "action": {"http_url": "https://example.com/hook", "http_method": "post"},
+		"simple_triggers": [{"type": "data_trigger", "on": "incoming_data", "interface_name": "org.Same",
+		"interface_major": 0, "match_path": "/*", "value_match_operator": "*"}]}`
+)
+
+// newTestKeyPair returns a PEM encoded ECDSA private key and its public key
+func newTestKeyPair(t *testing.T) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	private, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	public, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: private}),
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: public})
+}
+
+func testInterface(t *testing.T, name string, minor int, mappingType string) interfaces.AstarteInterface {
+	t.Helper()
+	iface, err := interfaces.ParseInterface([]byte(fmt.Sprintf(installedInterface, name, minor, mappingType)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return interfaces.EnsureInterfaceDefaults(iface)
+}
+
+func testObject(t *testing.T, spec string) map[string]interface{} {
+	t.Helper()
+	object := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(spec), &object); err != nil {
+		t.Fatal(err)
+	}
+	return object
+}
+
+// newTestRealm starts a mock server with realm test, which has interfaces org.Same, org.Older,
+// org.Newer and org.Diverged, policy retry and trigger on_value installed
+func newTestRealm(t *testing.T) *client.Client {
+	t.Helper()
+	_, housekeepingPublic := newTestKeyPair(t)
+	realmPrivate, realmPublic := newTestKeyPair(t)
+
+	realm := &mockserver.Realm{
+		Interfaces: []interfaces.AstarteInterface{
+			testInterface(t, "org.Same", 1, "double"),
+			testInterface(t, "org.Older", 1, "double"),
+			testInterface(t, "org.Newer", 3, "double"),
+			testInterface(t, "org.Diverged", 1, "double"),
+		},
+		Policies: []map[string]interface{}{testObject(t, installedPolicy)},
+		Triggers: []map[string]interface{}{testObject(t, installedTrigger)},
+	}
+	realm.Name = "test"
+	realm.JwtPublicKeyPEM = string(realmPublic)
+
+	server, err := mockserver.New(&mockserver.State{Realms: []*mockserver.Realm{realm}}, housekeepingPublic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+
+	c, err := client.New(client.WithBaseURL(ts.URL), client.WithPrivateKey(realmPrivate))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func testResource(kind Kind, name, spec string) Resource {
+	return Resource{Kind: kind, Name: name, Source: "test", Spec: json.RawMessage(spec)}
+}
+
+// testManifest describes every case NewPlan handles, in no particular order
+func testManifest() []Resource {
+	return []Resource{
+		testResource(KindTrigger, "on_value", installedTrigger),
+		testResource(KindTrigger, "orphan", `{"name": "orphan", "policy": "missing",
+			"action": {"http_url": "https://example.com/hook", "http_method": "post"},
+			"simple_triggers": [{"type": "data_trigger", "on": "incoming_data", "interface_name": "org.Same",
+			"interface_major": 0, "match_path": "/*", "value_match_operator": "*"}]}`),
+		testResource(KindTrigger, "on_new", `{"name": "on_new", "policy": "discard",
+			"action": {"http_url": "https://example.com/hook", "http_method": "post"},
+			"simple_triggers": [{"type": "data_trigger", "on": "incoming_data", "interface_name": "org.New",
+			"interface_major": 0, "match_path": "/*", "value_match_operator": "*"}]}`),
+		testResource(KindInterface, InterfaceResourceName("org.Same", 0), fmt.Sprintf(installedInterface, "org.Same", 1, "double")),
+		testResource(KindInterface, InterfaceResourceName("org.Older", 0), fmt.Sprintf(installedInterface, "org.Older", 2, "double")),
+		testResource(KindInterface, InterfaceResourceName("org.Newer", 0), fmt.Sprintf(installedInterface, "org.Newer", 2, "double")),
+		testResource(KindInterface, InterfaceResourceName("org.Diverged", 0), fmt.Sprintf(installedInterface, "org.Diverged", 1, "integer")),
+		testResource(KindInterface, InterfaceResourceName("org.New", 0), fmt.Sprintf(installedInterface, "org.New", 1, "double")),
+		testResource(KindTriggerDeliveryPolicy, "retry", `{"name": "retry", "error_handlers": [{"on": "any_error", "strategy": "discard"}]}`),
+		testResource(KindTriggerDeliveryPolicy, "discard", `{"name": "discard", "error_handlers": [{"on": "any_error", "strategy": "discard"}]}`),
+	}
+}
+
+func planActions(plan Plan) []string {
+	actions := []string{}
+	for _, c := range plan.Changes {
+		actions = append(actions, string(c.Kind)+" "+c.Name+": "+string(c.Action))
+	}
+	return actions
+}
+
+func TestNewPlan(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		want []string
+	}{
+		{
+			name: "default",
+			want: []string{
+				"TriggerDeliveryPolicy discard: create",
+				"TriggerDeliveryPolicy retry: skip",
+				"Interface " + InterfaceResourceName("org.Diverged", 0) + ": skip",
+				"Interface " + InterfaceResourceName("org.New", 0) + ": create",
+				"Interface " + InterfaceResourceName("org.Newer", 0) + ": skip",
+				"Interface " + InterfaceResourceName("org.Older", 0) + ": update",
+				"Interface " + InterfaceResourceName("org.Same", 0) + ": unchanged",
+				"Trigger on_new: create",
+				"Trigger on_value: unchanged",
+				"Trigger orphan: skip",
+			},
+		},
+		{
+			name: "force",
+			opts: Options{Force: true},
+			want: []string{
+				"TriggerDeliveryPolicy discard: create",
+				"TriggerDeliveryPolicy retry: replace",
+				"Interface " + InterfaceResourceName("org.Diverged", 0) + ": skip",
+				"Interface " + InterfaceResourceName("org.New", 0) + ": create",
+				"Interface " + InterfaceResourceName("org.Newer", 0) + ": skip",
+				"Interface " + InterfaceResourceName("org.Older", 0) + ": update",
+				"Interface " + InterfaceResourceName("org.Same", 0) + ": unchanged",
+				"Trigger on_new: create",
+				"Trigger on_value: unchanged",
+				"Trigger orphan: skip",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestRealm(t)
+			plan, err := NewPlan(c, "test", testManifest(), tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := planActions(plan); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewPlan() = %q, want %q", got, tt.want)
+			}
+			if !plan.HasChanges() {
+				t.Error("HasChanges() = false, want true")
+			}
+		})
+	}
+}
+
+func TestApplyConverges(t *testing.T) {
+	c := newTestRealm(t)
+	resources := testManifest()
+	plan, err := NewPlan(c, "test", resources, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Apply(c, plan, io.Discard); err != nil {
+		t.Fatal(err)
+	}
+
+	// Skipped resources stay skipped, everything else must now match the realm
+	plan, err = NewPlan(c, "test", resources, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.HasChanges() {
+		t.Errorf("plan after Apply has changes: %q", planActions(plan))
+	}
+}
@@ -0,0 +1,557 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mockserver
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/astarte-platform/astarte-go/client"
+	"github.com/astarte-platform/astarte-go/interfaces"
+)
+
+// defaultPageSize is the number of devices returned per page when no limit is given
+const defaultPageSize = 1000
+
+// deviceDetails is the representation of a device returned by AppEngine
+type deviceDetails struct {
+	client.DeviceDetails
+	Groups []string `json:"groups"`
+}
+
+func (s *Server) serveAppEngine(w http.ResponseWriter, r *http.Request, realm *Realm, p []string) {
+	switch {
+	case len(p) == 1 && p[0] == "devices" && r.Method == http.MethodGet:
+		writeDevicePage(w, r, realm, "devices", realm.Devices)
+
+	case len(p) >= 2 && (p[0] == "devices" || p[0] == "devices-by-alias"):
+		var device *Device
+		if p[0] == "devices" {
+			device = realm.device(p[1])
+		} else {
+			device = realm.deviceByAlias(p[1])
+		}
+		if device == nil {
+			writeError(w, http.StatusNotFound, "Device not found")
+			return
+		}
+		serveDevice(w, r, realm, device, p[2:])
+
+	case len(p) == 2 && p[0] == "stats" && p[1] == "devices" && r.Method == http.MethodGet:
+		stats := client.DevicesStats{TotalDevices: int64(len(realm.Devices))}
+		for _, d := range realm.Devices {
+			if d.Connected {
+				stats.ConnectedDevices++
+			}
+		}
+		writeData(w, http.StatusOK, stats)
+
+	case len(p) >= 1 && p[0] == "groups":
+		serveGroups(w, r, realm, p[1:])
+
+	default:
+		writeError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+// writeDevicePage writes a page of devices, paginated through the from_token and limit parameters
+func writeDevicePage(w http.ResponseWriter, r *http.Request, realm *Realm, basePath string, devices []*Device) {
+	query := r.URL.Query()
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultPageSize
+	}
+	from, err := strconv.Atoi(query.Get("from_token"))
+	if err != nil || from < 0 {
+		from = 0
+	}
+	details := query.Get("details") == "true"
+
+	sorted := append([]*Device{}, devices...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].DeviceID < sorted[j].DeviceID })
+	if from > len(sorted) {
+		from = len(sorted)
+	}
+	to := from + limit
+	if to > len(sorted) {
+		to = len(sorted)
+	}
+
+	page := []interface{}{}
+	for _, d := range sorted[from:to] {
+		if details {
+			page = append(page, d.details())
+		} else {
+			page = append(page, d.DeviceID)
+		}
+	}
+
+	links := client.Links{Self: fmt.Sprintf("/v1/%s/%s?%s", realm.Name, basePath, query.Encode())}
+	if to < len(sorted) {
+		next := url.Values{}
+		next.Set("details", strconv.FormatBool(details))
+		next.Set("from_token", strconv.Itoa(to))
+		next.Set("limit", strconv.Itoa(limit))
+		links.Next = fmt.Sprintf("/v1/%s/%s?%s", realm.Name, basePath, next.Encode())
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": page, "links": links})
+}
+
+func (d *Device) details() deviceDetails {
+	groups := append([]string{}, d.Groups...)
+	sort.Strings(groups)
+	return deviceDetails{DeviceDetails: d.DeviceDetails, Groups: groups}
+}
+
+func serveDevice(w http.ResponseWriter, r *http.Request, realm *Realm, device *Device, p []string) {
+	switch {
+	case len(p) == 0 && r.Method == http.MethodGet:
+		writeData(w, http.StatusOK, device.details())
+
+	case len(p) == 0 && r.Method == http.MethodPatch:
+		patch := struct {
+			Aliases              map[string]*string `json:"aliases"`
+			Attributes           map[string]*string `json:"attributes"`
+			CredentialsInhibited *bool              `json:"credentials_inhibited"`
+		}{}
+		if err := readData(r, &patch); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		for tag, alias := range patch.Aliases {
+			if alias == nil {
+				delete(device.Aliases, tag)
+				continue
+			}
+			if other := realm.deviceByAlias(*alias); other != nil && other != device {
+				writeError(w, http.StatusConflict, "Alias already in use")
+				return
+			}
+			device.Aliases[tag] = *alias
+		}
+		for key, value := range patch.Attributes {
+			if value == nil {
+				delete(device.Attributes, key)
+			} else {
+				device.Attributes[key] = *value
+			}
+		}
+		if patch.CredentialsInhibited != nil {
+			device.CredentialsInhibited = *patch.CredentialsInhibited
+		}
+		writeData(w, http.StatusOK, device.details())
+
+	case len(p) == 1 && p[0] == "interfaces" && r.Method == http.MethodGet:
+		names := []string{}
+		for name := range device.Introspection {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		writeData(w, http.StatusOK, names)
+
+	case len(p) >= 2 && p[0] == "interfaces":
+		interfacePath := ""
+		if len(p) > 2 {
+			interfacePath = "/" + strings.Join(p[2:], "/")
+		}
+		serveDeviceData(w, r, realm, device, p[1], interfacePath)
+
+	default:
+		writeError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+// deviceInterface returns the interface used by device: the one in its introspection if any,
+// otherwise the most recent installed major version
+func deviceInterface(realm *Realm, device *Device, name string) (interfaces.AstarteInterface, bool) {
+	majors := realm.interfaceMajors(name)
+	if len(majors) == 0 {
+		return interfaces.AstarteInterface{}, false
+	}
+	major := majors[len(majors)-1]
+	if introspection, ok := device.Introspection[name]; ok {
+		major = introspection.Major
+	}
+	idx := realm.findInterface(name, major)
+	if idx < 0 {
+		return interfaces.AstarteInterface{}, false
+	}
+	return realm.Interfaces[idx], true
+}
+
+func serveDeviceData(w http.ResponseWriter, r *http.Request, realm *Realm, device *Device, interfaceName, interfacePath string) {
+	iface, ok := deviceInterface(realm, device, interfaceName)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Interface not found")
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		if iface.Ownership != interfaces.ServerOwnership {
+			writeError(w, http.StatusForbidden, "Cannot write to device owned interface")
+			return
+		}
+		// Writing to an interface makes it part of the introspection, as if the device declared it
+		device.Introspection[iface.Name] = client.DeviceInterfaceIntrospection{Major: iface.MajorVersion, Minor: iface.MinorVersion}
+	}
+
+	switch {
+	case iface.Type == interfaces.PropertiesType:
+		serveProperties(w, r, device, iface, interfacePath)
+	case iface.Aggregation == interfaces.ObjectAggregation:
+		serveObjectDatastream(w, r, device, iface, interfacePath)
+	default:
+		serveIndividualDatastream(w, r, device, iface, interfacePath)
+	}
+}
+
+func serveProperties(w http.ResponseWriter, r *http.Request, device *Device, iface interfaces.AstarteInterface, interfacePath string) {
+	properties := device.Properties[iface.Name]
+
+	switch r.Method {
+	case http.MethodGet:
+		if value, ok := properties[interfacePath]; ok && interfacePath != "" {
+			writeData(w, http.StatusOK, value)
+			return
+		}
+		values := map[string]interface{}{}
+		for p, v := range properties {
+			values[p] = v
+		}
+		writeSubtree(w, values, interfacePath)
+
+	case http.MethodPut:
+		if err := interfaces.ValidateInterfacePath(iface, interfacePath); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		var value interface{}
+		if err := readData(r, &value); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if properties == nil {
+			properties = map[string]interface{}{}
+			device.Properties[iface.Name] = properties
+		}
+		properties[interfacePath] = value
+		writeData(w, http.StatusOK, value)
+
+	case http.MethodDelete:
+		if _, ok := properties[interfacePath]; !ok {
+			writeError(w, http.StatusNotFound, "Path not found")
+			return
+		}
+		delete(properties, interfacePath)
+		writeNoContent(w)
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func serveIndividualDatastream(w http.ResponseWriter, r *http.Request, device *Device, iface interfaces.AstarteInterface, interfacePath string) {
+	switch r.Method {
+	case http.MethodGet:
+		samples := device.Samples[iface.Name]
+		if series, ok := samples[interfacePath]; ok && interfacePath != "" {
+			writeSeries(w, r, series)
+			return
+		}
+		// Without a complete path, the last value of each path is returned
+		values := map[string]interface{}{}
+		for p, series := range samples {
+			if len(series) > 0 {
+				values[p] = latest(series)
+			}
+		}
+		writeSubtree(w, values, interfacePath)
+
+	case http.MethodPost:
+		if err := interfaces.ValidateInterfacePath(iface, interfacePath); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		var value interface{}
+		if err := readData(r, &value); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		now := time.Now().UTC().Format(time.RFC3339Nano)
+		appendSample(device, iface.Name, interfacePath, Sample{"value": value, "timestamp": now, "reception_timestamp": now})
+		writeData(w, http.StatusOK, value)
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func serveObjectDatastream(w http.ResponseWriter, r *http.Request, device *Device, iface interfaces.AstarteInterface, interfacePath string) {
+	switch r.Method {
+	case http.MethodGet:
+		samples := device.Samples[iface.Name]
+		if series, ok := samples[interfacePath]; ok && interfacePath != "" {
+			writeSeries(w, r, series)
+			return
+		}
+		values := map[string]interface{}{}
+		for p, series := range samples {
+			selected, err := querySeries(r, series)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			values[p] = selected
+		}
+		writeSubtree(w, values, interfacePath)
+
+	case http.MethodPost:
+		value := map[string]interface{}{}
+		if err := readData(r, &value); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		for k := range value {
+			if err := interfaces.ValidateInterfacePath(iface, path.Join(interfacePath, k)); err != nil {
+				writeError(w, http.StatusUnprocessableEntity, err.Error())
+				return
+			}
+		}
+		sample := Sample{"timestamp": time.Now().UTC().Format(time.RFC3339Nano)}
+		for k, v := range value {
+			sample[k] = v
+		}
+		appendSample(device, iface.Name, interfacePath, sample)
+		writeData(w, http.StatusOK, value)
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func appendSample(device *Device, interfaceName, interfacePath string, sample Sample) {
+	if device.Samples[interfaceName] == nil {
+		device.Samples[interfaceName] = map[string][]Sample{}
+	}
+	device.Samples[interfaceName][interfacePath] = append(device.Samples[interfaceName][interfacePath], sample)
+}
+
+func latest(series []Sample) Sample {
+	ret := series[0]
+	for _, s := range series[1:] {
+		if !s.Timestamp().Before(ret.Timestamp()) {
+			ret = s
+		}
+	}
+	return ret
+}
+
+func writeSeries(w http.ResponseWriter, r *http.Request, series []Sample) {
+	selected, err := querySeries(r, series)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeData(w, http.StatusOK, selected)
+}
+
+// querySeries applies the since, since_after, to and limit parameters to series. Like Astarte,
+// when no lower bound is given the most recent samples are returned, in descending order.
+func querySeries(r *http.Request, series []Sample) ([]Sample, error) {
+	query := r.URL.Query()
+	bounds := map[string]time.Time{}
+	for _, b := range []string{"since", "since_after", "to"} {
+		if raw := query.Get(b); raw != "" {
+			t, err := time.Parse(time.RFC3339Nano, raw)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", b, err)
+			}
+			bounds[b] = t
+		}
+	}
+	limit, _ := strconv.Atoi(query.Get("limit"))
+
+	sorted := append([]Sample{}, series...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Timestamp().Before(sorted[j].Timestamp()) })
+
+	selected := []Sample{}
+	for _, s := range sorted {
+		t := s.Timestamp()
+		if since, ok := bounds["since"]; ok && t.Before(since) {
+			continue
+		}
+		if sinceAfter, ok := bounds["since_after"]; ok && !t.After(sinceAfter) {
+			continue
+		}
+		if to, ok := bounds["to"]; ok && !t.Before(to) {
+			continue
+		}
+		selected = append(selected, s)
+	}
+
+	_, hasSince := bounds["since"]
+	_, hasSinceAfter := bounds["since_after"]
+	if limit <= 0 || limit >= len(selected) {
+		if limit > 0 && !hasSince && !hasSinceAfter {
+			reverse(selected)
+		}
+		return selected, nil
+	}
+	if hasSince || hasSinceAfter {
+		return selected[:limit], nil
+	}
+	selected = selected[len(selected)-limit:]
+	reverse(selected)
+	return selected, nil
+}
+
+func reverse(samples []Sample) {
+	for i, j := 0, len(samples)-1; i < j; i, j = i+1, j-1 {
+		samples[i], samples[j] = samples[j], samples[i]
+	}
+}
+
+// writeSubtree writes the values below prefix as a tree of nested objects, keyed by path segments
+func writeSubtree(w http.ResponseWriter, values map[string]interface{}, prefix string) {
+	tree := map[string]interface{}{}
+	found := false
+	for p, v := range values {
+		if prefix != "" && !strings.HasPrefix(p, prefix+"/") {
+			continue
+		}
+		found = true
+		node := tree
+		segments := strings.Split(strings.Trim(strings.TrimPrefix(p, prefix), "/"), "/")
+		for _, segment := range segments[:len(segments)-1] {
+			child, ok := node[segment].(map[string]interface{})
+			if !ok {
+				child = map[string]interface{}{}
+				node[segment] = child
+			}
+			node = child
+		}
+		node[segments[len(segments)-1]] = v
+	}
+	if prefix != "" && !found {
+		writeError(w, http.StatusNotFound, "Path not found")
+		return
+	}
+	writeData(w, http.StatusOK, tree)
+}
+
+func serveGroups(w http.ResponseWriter, r *http.Request, realm *Realm, p []string) {
+	switch {
+	case len(p) == 0 && r.Method == http.MethodGet:
+		writeData(w, http.StatusOK, realm.groupNames())
+
+	case len(p) == 0 && r.Method == http.MethodPost:
+		group := client.DevicesAndGroup{}
+		if err := readData(r, &group); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if group.GroupName == "" || strings.HasPrefix(group.GroupName, "~") || strings.HasPrefix(group.GroupName, "@") {
+			writeError(w, http.StatusUnprocessableEntity, "Invalid group name")
+			return
+		}
+		if len(group.Devices) == 0 {
+			writeError(w, http.StatusUnprocessableEntity, "A group must contain at least one device")
+			return
+		}
+		if len(realm.groupDevices(group.GroupName)) > 0 {
+			writeError(w, http.StatusConflict, "Group already exists")
+			return
+		}
+		devices := []*Device{}
+		for _, id := range group.Devices {
+			device := realm.device(id)
+			if device == nil {
+				writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("Device %s not found", id))
+				return
+			}
+			devices = append(devices, device)
+		}
+		for _, d := range devices {
+			if !d.inGroup(group.GroupName) {
+				d.Groups = append(d.Groups, group.GroupName)
+			}
+		}
+		writeData(w, http.StatusCreated, group)
+
+	case len(p) >= 2 && p[1] == "devices":
+		groupName := unescape(p[0])
+		members := realm.groupDevices(groupName)
+		// Groups only exist as long as they have devices
+		if len(members) == 0 {
+			writeError(w, http.StatusNotFound, "Group not found")
+			return
+		}
+		serveGroupDevices(w, r, realm, groupName, members, p[2:])
+
+	default:
+		writeError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+func serveGroupDevices(w http.ResponseWriter, r *http.Request, realm *Realm, groupName string, members []*Device, p []string) {
+	switch {
+	case len(p) == 0 && r.Method == http.MethodGet:
+		writeDevicePage(w, r, realm, "groups/"+url.PathEscape(groupName)+"/devices", members)
+
+	case len(p) == 0 && r.Method == http.MethodPost:
+		body := struct {
+			DeviceID string `json:"device_id"`
+		}{}
+		if err := readData(r, &body); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		device := realm.device(body.DeviceID)
+		if device == nil {
+			writeError(w, http.StatusNotFound, "Device not found")
+			return
+		}
+		if device.inGroup(groupName) {
+			writeError(w, http.StatusConflict, "Device already in group")
+			return
+		}
+		device.Groups = append(device.Groups, groupName)
+		writeData(w, http.StatusCreated, body)
+
+	case len(p) == 1 && r.Method == http.MethodDelete:
+		device := realm.device(p[0])
+		if device == nil || !device.inGroup(groupName) {
+			writeError(w, http.StatusNotFound, "Device not found")
+			return
+		}
+		groups := []string{}
+		for _, g := range device.Groups {
+			if g != groupName {
+				groups = append(groups, g)
+			}
+		}
+		device.Groups = groups
+		writeNoContent(w)
+
+	default:
+		writeError(w, http.StatusNotFound, "Not found")
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mockserver
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestAppEngineDevices(t *testing.T) {
+	s := newTestServer(t)
+	s.run(t, s.realmToken(t, "a_pa"), []step{
+		{http.MethodPost, "/pairing/v1/test/agent/devices", map[string]interface{}{"hw_id": "f0VMRgIBAQAAAAAAAAAAAA"}, http.StatusCreated, ""},
+	})
+
+	s.run(t, s.realmToken(t, "a_aea"), []step{
+		{http.MethodGet, "/appengine/v1/test/devices", nil, http.StatusOK, `["2TBn-jNESuuHamE2Zo1anA", "f0VMRgIBAQAAAAAAAAAAAA"]`},
+		{http.MethodGet, "/appengine/v1/test/devices?limit=1", nil, http.StatusOK, `["2TBn-jNESuuHamE2Zo1anA"]`},
+		{http.MethodGet, "/appengine/v1/test/devices?limit=1&from_token=1", nil, http.StatusOK, `["f0VMRgIBAQAAAAAAAAAAAA"]`},
+		{http.MethodGet, "/appengine/v1/test/stats/devices", nil, http.StatusOK, `{"total_devices": 2, "connected_devices": 1}`},
+		{http.MethodGet, "/appengine/v1/test/devices/" + testDeviceID + "/interfaces", nil, http.StatusOK, `["org.Values"]`},
+		{http.MethodGet, "/appengine/v1/test/devices/AAAAAAAAAAAAAAAAAAAAAA", nil, http.StatusNotFound, ""},
+		{http.MethodPatch, "/appengine/v1/test/devices/" + testDeviceID, map[string]interface{}{"aliases": map[string]string{"name": "sensor"}},
+			http.StatusOK, ""},
+		{http.MethodPatch, "/appengine/v1/test/devices/f0VMRgIBAQAAAAAAAAAAAA", map[string]interface{}{"aliases": map[string]string{"name": "sensor"}},
+			http.StatusConflict, ""},
+		{http.MethodGet, "/appengine/v1/test/devices-by-alias/sensor/interfaces", nil, http.StatusOK, `["org.Values"]`},
+	})
+}
+
+func TestAppEngineData(t *testing.T) {
+	s := newTestServer(t)
+	s.run(t, s.realmToken(t, "a_aea"), []step{
+		{http.MethodGet, "/appengine/v1/test/devices/" + testDeviceID + "/interfaces/org.Values", nil, http.StatusOK,
+			`{"s1": {"value": {"value": 2, "timestamp": "2026-01-02T00:00:00Z"}}}`},
+		{http.MethodGet, "/appengine/v1/test/devices/" + testDeviceID + "/interfaces/org.Values/s1/value", nil, http.StatusOK,
+			`[{"value": 1, "timestamp": "2026-01-01T00:00:00Z"}, {"value": 2, "timestamp": "2026-01-02T00:00:00Z"}]`},
+		{http.MethodGet, "/appengine/v1/test/devices/" + testDeviceID + "/interfaces/org.Values/s1/value?limit=1", nil, http.StatusOK,
+			`[{"value": 2, "timestamp": "2026-01-02T00:00:00Z"}]`},
+		{http.MethodGet, "/appengine/v1/test/devices/" + testDeviceID + "/interfaces/org.Values/s1/value?since=2026-01-02T00:00:00Z", nil, http.StatusOK,
+			`[{"value": 2, "timestamp": "2026-01-02T00:00:00Z"}]`},
+		{http.MethodGet, "/appengine/v1/test/devices/" + testDeviceID + "/interfaces/org.Values/s1/value?since=yesterday", nil, http.StatusBadRequest, ""},
+		{http.MethodGet, "/appengine/v1/test/devices/" + testDeviceID + "/interfaces/org.Values/s2", nil, http.StatusNotFound, ""},
+		{http.MethodPost, "/appengine/v1/test/devices/" + testDeviceID + "/interfaces/org.Values/s1/value", 3, http.StatusForbidden, ""},
+		{http.MethodGet, "/appengine/v1/test/devices/" + testDeviceID + "/interfaces/org.Missing", nil, http.StatusNotFound, ""},
+	})
+}
+
+func TestAppEngineGroups(t *testing.T) {
+	s := newTestServer(t)
+	s.run(t, s.realmToken(t, "a_aea"), []step{
+		{http.MethodGet, "/appengine/v1/test/groups", nil, http.StatusOK, `[]`},
+		{http.MethodPost, "/appengine/v1/test/groups", map[string]interface{}{"group_name": "fleet", "devices": []string{}},
+			http.StatusUnprocessableEntity, ""},
+		{http.MethodPost, "/appengine/v1/test/groups", map[string]interface{}{"group_name": "~fleet", "devices": []string{testDeviceID}},
+			http.StatusUnprocessableEntity, ""},
+		{http.MethodPost, "/appengine/v1/test/groups", map[string]interface{}{"group_name": "fleet", "devices": []string{"AAAAAAAAAAAAAAAAAAAAAA"}},
+			http.StatusUnprocessableEntity, ""},
+		{http.MethodPost, "/appengine/v1/test/groups", map[string]interface{}{"group_name": "fleet", "devices": []string{testDeviceID}},
+			http.StatusCreated, ""},
+		{http.MethodPost, "/appengine/v1/test/groups", map[string]interface{}{"group_name": "fleet", "devices": []string{testDeviceID}},
+			http.StatusConflict, ""},
+		{http.MethodGet, "/appengine/v1/test/groups", nil, http.StatusOK, `["fleet"]`},
+		{http.MethodGet, "/appengine/v1/test/groups/fleet/devices", nil, http.StatusOK, `["2TBn-jNESuuHamE2Zo1anA"]`},
+		{http.MethodPost, "/appengine/v1/test/groups/fleet/devices", map[string]interface{}{"device_id": testDeviceID}, http.StatusConflict, ""},
+		{http.MethodDelete, "/appengine/v1/test/groups/fleet/devices/" + testDeviceID, nil, http.StatusNoContent, ""},
+		// Groups only exist as long as they have devices
+		{http.MethodGet, "/appengine/v1/test/groups/fleet/devices", nil, http.StatusNotFound, ""},
+	})
+}
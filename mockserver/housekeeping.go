@@ -0,0 +1,143 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mockserver
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"sort"
+
+	"github.com/astarte-platform/astarte-go/client"
+)
+
+var realmNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9]{0,47}$`)
+
+func (s *Server) serveHousekeeping(w http.ResponseWriter, r *http.Request, path []string) {
+	switch {
+	case len(path) == 1 && path[0] == "realms" && r.Method == http.MethodGet:
+		realms := []string{}
+		for _, realm := range s.state.Realms {
+			realms = append(realms, realm.Name)
+		}
+		sort.Strings(realms)
+		writeData(w, http.StatusOK, realms)
+
+	case len(path) == 1 && path[0] == "realms" && r.Method == http.MethodPost:
+		details := client.RealmDetails{}
+		if err := readData(r, &details); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if !realmNameRegexp.MatchString(details.Name) {
+			writeError(w, http.StatusUnprocessableEntity, "Invalid realm name")
+			return
+		}
+		if _, err := parsePublicKey(details.JwtPublicKeyPEM); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, "Invalid JWT public key")
+			return
+		}
+		if s.state.realm(details.Name) != nil {
+			writeError(w, http.StatusConflict, "Realm already exists")
+			return
+		}
+		switch {
+		case details.ReplicationClass != "":
+		case len(details.DatacenterReplicationFactors) > 0:
+			details.ReplicationClass = "NetworkTopologyStrategy"
+		default:
+			details.ReplicationClass = "SimpleStrategy"
+			if details.ReplicationFactor == 0 {
+				details.ReplicationFactor = 1
+			}
+		}
+		realm := &Realm{RealmDetails: details}
+		s.state.Realms = append(s.state.Realms, realm)
+		writeData(w, http.StatusCreated, realm.details())
+
+	case len(path) == 2 && path[0] == "realms" && r.Method == http.MethodGet:
+		realm := s.state.realm(path[1])
+		if realm == nil {
+			writeError(w, http.StatusNotFound, "Realm not found")
+			return
+		}
+		writeData(w, http.StatusOK, realm.details())
+
+	case len(path) == 2 && path[0] == "realms" && r.Method == http.MethodPatch:
+		realm := s.state.realm(path[1])
+		if realm == nil {
+			writeError(w, http.StatusNotFound, "Realm not found")
+			return
+		}
+		// A JSON merge patch, where null removes limits
+		patch := map[string]json.RawMessage{}
+		if err := readData(r, &patch); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		for field, value := range patch {
+			var err error
+			switch field {
+			case "jwt_public_key_pem":
+				var publicKey string
+				if err = json.Unmarshal(value, &publicKey); err == nil {
+					if _, keyErr := parsePublicKey(publicKey); keyErr != nil {
+						writeError(w, http.StatusUnprocessableEntity, "Invalid JWT public key")
+						return
+					}
+				}
+			case "device_registration_limit", "datastream_maximum_storage_retention":
+				var limit *int
+				if err = json.Unmarshal(value, &limit); err == nil && limit != nil && *limit < 0 {
+					err = errors.New("limits can't be negative")
+				}
+			default:
+				err = errors.New(field + " can't be changed")
+			}
+			if err != nil {
+				writeError(w, http.StatusUnprocessableEntity, err.Error())
+				return
+			}
+		}
+		// Fields are validated, apply them all
+		for field, value := range patch {
+			switch field {
+			case "jwt_public_key_pem":
+				_ = json.Unmarshal(value, &realm.JwtPublicKeyPEM)
+			case "device_registration_limit":
+				realm.DeviceRegistrationLimit = nil
+				_ = json.Unmarshal(value, &realm.DeviceRegistrationLimit)
+			case "datastream_maximum_storage_retention":
+				realm.DatastreamMaximumStorageRetention = nil
+				_ = json.Unmarshal(value, &realm.DatastreamMaximumStorageRetention)
+			}
+		}
+		writeData(w, http.StatusOK, realm.details())
+
+	case len(path) == 2 && path[0] == "realms" && r.Method == http.MethodDelete:
+		for i, realm := range s.state.Realms {
+			if realm.Name == path[1] {
+				s.state.Realms = append(s.state.Realms[:i], s.state.Realms[i+1:]...)
+				writeNoContent(w)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "Realm not found")
+
+	default:
+		writeError(w, http.StatusNotFound, "Not found")
+	}
+}
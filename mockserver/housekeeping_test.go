@@ -0,0 +1,79 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mockserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestHousekeeping(t *testing.T) {
+	s := newTestServer(t)
+	_, publicKey := newTestKey(t)
+
+	s.run(t, s.housekeepingToken(t), []step{
+		{http.MethodGet, "/housekeeping/v1/realms", nil, http.StatusOK, `["test"]`},
+		{http.MethodPost, "/housekeeping/v1/realms", map[string]interface{}{"realm_name": "other", "jwt_public_key_pem": publicKey},
+			http.StatusCreated, ""},
+		{http.MethodPost, "/housekeeping/v1/realms", map[string]interface{}{"realm_name": "other", "jwt_public_key_pem": publicKey},
+			http.StatusConflict, ""},
+		{http.MethodPost, "/housekeeping/v1/realms", map[string]interface{}{"realm_name": "Invalid-Name", "jwt_public_key_pem": publicKey},
+			http.StatusUnprocessableEntity, ""},
+		{http.MethodPost, "/housekeeping/v1/realms", map[string]interface{}{"realm_name": "nokey", "jwt_public_key_pem": "not a key"},
+			http.StatusUnprocessableEntity, ""},
+		{http.MethodGet, "/housekeeping/v1/realms", nil, http.StatusOK, `["other", "test"]`},
+		{http.MethodPatch, "/housekeeping/v1/realms/other", map[string]interface{}{"device_registration_limit": 10},
+			http.StatusOK, ""},
+		{http.MethodPatch, "/housekeeping/v1/realms/other", map[string]interface{}{"device_registration_limit": -1},
+			http.StatusUnprocessableEntity, ""},
+		{http.MethodPatch, "/housekeeping/v1/realms/other", map[string]interface{}{"replication_factor": 3},
+			http.StatusUnprocessableEntity, ""},
+		{http.MethodDelete, "/housekeeping/v1/realms/other", nil, http.StatusNoContent, ""},
+		{http.MethodGet, "/housekeeping/v1/realms/other", nil, http.StatusNotFound, ""},
+		{http.MethodDelete, "/housekeeping/v1/realms/other", nil, http.StatusNotFound, ""},
+	})
+}
+
+func TestHousekeepingRealmDetails(t *testing.T) {
+	s := newTestServer(t)
+	_, publicKey := newTestKey(t)
+
+	s.run(t, s.housekeepingToken(t), []step{
+		{http.MethodPost, "/housekeeping/v1/realms", map[string]interface{}{"realm_name": "other", "jwt_public_key_pem": publicKey},
+			http.StatusCreated, ""},
+		{http.MethodPatch, "/housekeeping/v1/realms/other", map[string]interface{}{"device_registration_limit": 10},
+			http.StatusOK, ""},
+	})
+
+	// Replication defaults to a single replica, and limits are reported even when unset
+	status, data := s.do(t, http.MethodGet, "/housekeeping/v1/realms/other", s.housekeepingToken(t), nil)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	details := realmDetails{}
+	if err := json.Unmarshal(data, &details); err != nil {
+		t.Fatal(err)
+	}
+	if details.ReplicationClass != "SimpleStrategy" || details.ReplicationFactor != 1 {
+		t.Errorf("replication = %s, %d, want SimpleStrategy, 1", details.ReplicationClass, details.ReplicationFactor)
+	}
+	if details.DeviceRegistrationLimit == nil || *details.DeviceRegistrationLimit != 10 {
+		t.Errorf("device_registration_limit = %v, want 10", details.DeviceRegistrationLimit)
+	}
+	if details.DatastreamMaximumStorageRetention != nil {
+		t.Errorf("datastream_maximum_storage_retention = %d, want none", *details.DatastreamMaximumStorageRetention)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mockserver
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"time"
+
+	"github.com/astarte-platform/astarte-go/client"
+	"github.com/astarte-platform/astarte-go/deviceid"
+)
+
+func (s *Server) servePairing(w http.ResponseWriter, r *http.Request, realm *Realm, path []string) {
+	if len(path) < 2 || path[0] != "agent" || path[1] != "devices" {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	switch {
+	case len(path) == 2 && r.Method == http.MethodPost:
+		body := struct {
+			HwID string `json:"hw_id"`
+		}{}
+		if err := readData(r, &body); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if !deviceid.IsValid(body.HwID) {
+			writeError(w, http.StatusUnprocessableEntity, "Invalid device ID")
+			return
+		}
+
+		device := realm.device(body.HwID)
+		if device != nil && device.CredentialsSecret != "" {
+			writeError(w, http.StatusUnprocessableEntity, "Device already registered")
+			return
+		}
+		if device == nil && realm.DeviceRegistrationLimit != nil && len(realm.Devices) >= *realm.DeviceRegistrationLimit {
+			writeError(w, http.StatusUnprocessableEntity, "Device registration limit reached")
+			return
+		}
+		if device == nil {
+			device = &Device{DeviceDetails: client.DeviceDetails{DeviceID: body.HwID, FirstRegistration: time.Now().UTC()}}
+			device.ensureMaps()
+			realm.Devices = append(realm.Devices, device)
+		}
+
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		device.CredentialsSecret = base64.StdEncoding.EncodeToString(secret)
+		writeData(w, http.StatusCreated, map[string]string{"credentials_secret": device.CredentialsSecret})
+
+	case len(path) == 3 && r.Method == http.MethodDelete:
+		device := realm.device(path[2])
+		if device == nil {
+			writeError(w, http.StatusNotFound, "Device not found")
+			return
+		}
+		// The device is kept, it just has to register again
+		device.CredentialsSecret = ""
+		writeNoContent(w)
+
+	default:
+		writeError(w, http.StatusNotFound, "Not found")
+	}
+}
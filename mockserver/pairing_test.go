@@ -0,0 +1,53 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mockserver
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestPairing(t *testing.T) {
+	s := newTestServer(t)
+	const newDeviceID = "f0VMRgIBAQAAAAAAAAAAAA"
+
+	s.run(t, s.realmToken(t, "a_pa"), []step{
+		{http.MethodPost, "/pairing/v1/test/agent/devices", map[string]interface{}{"hw_id": "not a device ID"}, http.StatusUnprocessableEntity, ""},
+		{http.MethodPost, "/pairing/v1/test/agent/devices", map[string]interface{}{"hw_id": newDeviceID}, http.StatusCreated, ""},
+		{http.MethodPost, "/pairing/v1/test/agent/devices", map[string]interface{}{"hw_id": newDeviceID}, http.StatusUnprocessableEntity, ""},
+		{http.MethodDelete, "/pairing/v1/test/agent/devices/" + newDeviceID, nil, http.StatusNoContent, ""},
+		// Unregistered devices can register again
+		{http.MethodPost, "/pairing/v1/test/agent/devices", map[string]interface{}{"hw_id": newDeviceID}, http.StatusCreated, ""},
+		{http.MethodDelete, "/pairing/v1/test/agent/devices/AAAAAAAAAAAAAAAAAAAAAA", nil, http.StatusNotFound, ""},
+	})
+	s.run(t, s.realmToken(t, "a_aea"), []step{
+		{http.MethodGet, "/appengine/v1/test/devices", nil, http.StatusOK, `["2TBn-jNESuuHamE2Zo1anA", "f0VMRgIBAQAAAAAAAAAAAA"]`},
+	})
+}
+
+func TestPairingRegistrationLimit(t *testing.T) {
+	s := newTestServer(t)
+	s.run(t, s.housekeepingToken(t), []step{
+		{http.MethodPatch, "/housekeeping/v1/realms/test", map[string]interface{}{"device_registration_limit": 1}, http.StatusOK, ""},
+	})
+
+	s.run(t, s.realmToken(t, "a_pa"), []step{
+		{http.MethodPost, "/pairing/v1/test/agent/devices", map[string]interface{}{"hw_id": "f0VMRgIBAQAAAAAAAAAAAA"},
+			http.StatusUnprocessableEntity, ""},
+		// Devices which are already in the realm don't count towards the limit
+		{http.MethodDelete, "/pairing/v1/test/agent/devices/" + testDeviceID, nil, http.StatusNoContent, ""},
+		{http.MethodPost, "/pairing/v1/test/agent/devices", map[string]interface{}{"hw_id": testDeviceID}, http.StatusCreated, ""},
+	})
+}
@@ -0,0 +1,235 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mockserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/astarte-platform/astarte-go/interfaces"
+	"github.com/astarte-platform/astarte-go/triggers"
+)
+
+func (s *Server) serveRealmManagement(w http.ResponseWriter, r *http.Request, realm *Realm, path []string) {
+	if len(path) == 0 {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	switch path[0] {
+	case "interfaces":
+		serveInterfaces(w, r, realm, path[1:])
+	case "triggers":
+		serveTriggers(w, r, realm, path[1:])
+	case "policies":
+		servePolicies(w, r, realm, path[1:])
+	default:
+		writeError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+func serveInterfaces(w http.ResponseWriter, r *http.Request, realm *Realm, path []string) {
+	switch {
+	case len(path) == 0 && r.Method == http.MethodGet:
+		writeData(w, http.StatusOK, realm.interfaceNames())
+
+	case len(path) == 0 && r.Method == http.MethodPost:
+		iface, ok := readInterface(w, r)
+		if !ok {
+			return
+		}
+		if realm.findInterface(iface.Name, iface.MajorVersion) >= 0 {
+			writeError(w, http.StatusConflict, "Interface already exists")
+			return
+		}
+		realm.Interfaces = append(realm.Interfaces, iface)
+		writeData(w, http.StatusCreated, iface)
+
+	case len(path) == 1 && r.Method == http.MethodGet:
+		majors := realm.interfaceMajors(path[0])
+		if len(majors) == 0 {
+			writeError(w, http.StatusNotFound, "Interface not found")
+			return
+		}
+		writeData(w, http.StatusOK, majors)
+
+	case len(path) == 2:
+		major, err := strconv.Atoi(path[1])
+		if err != nil {
+			writeError(w, http.StatusNotFound, "Interface not found")
+			return
+		}
+		idx := realm.findInterface(path[0], major)
+		if idx < 0 {
+			writeError(w, http.StatusNotFound, "Interface not found")
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			writeData(w, http.StatusOK, realm.Interfaces[idx])
+		case http.MethodPut:
+			iface, ok := readInterface(w, r)
+			if !ok {
+				return
+			}
+			if iface.Name != path[0] || iface.MajorVersion != major {
+				writeError(w, http.StatusConflict, "Interface name and major version don't match")
+				return
+			}
+			if iface.MinorVersion <= realm.Interfaces[idx].MinorVersion {
+				writeError(w, http.StatusConflict, "Interface minor version was not increased")
+				return
+			}
+			realm.Interfaces[idx] = iface
+			writeNoContent(w)
+		case http.MethodDelete:
+			if major != 0 {
+				writeError(w, http.StatusForbidden, "Only draft interfaces (major version 0) can be deleted")
+				return
+			}
+			realm.Interfaces = append(realm.Interfaces[:idx], realm.Interfaces[idx+1:]...)
+			writeNoContent(w)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+
+	default:
+		writeError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+func readInterface(w http.ResponseWriter, r *http.Request) (interfaces.AstarteInterface, bool) {
+	raw := json.RawMessage{}
+	if err := readData(r, &raw); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return interfaces.AstarteInterface{}, false
+	}
+	iface, err := interfaces.ParseInterface(raw)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return iface, false
+	}
+	return iface, true
+}
+
+func serveTriggers(w http.ResponseWriter, r *http.Request, realm *Realm, path []string) {
+	switch {
+	case len(path) == 0 && r.Method == http.MethodGet:
+		writeData(w, http.StatusOK, names(realm.Triggers))
+
+	case len(path) == 0 && r.Method == http.MethodPost:
+		raw := json.RawMessage{}
+		if err := readData(r, &raw); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if _, err := triggers.ParseTrigger(raw); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		trigger := map[string]interface{}{}
+		_ = json.Unmarshal(raw, &trigger)
+		name, _ := trigger["name"].(string)
+		if name == "" {
+			writeError(w, http.StatusUnprocessableEntity, "Trigger name is missing")
+			return
+		}
+		if findNamed(realm.Triggers, name) >= 0 {
+			writeError(w, http.StatusConflict, "Trigger already exists")
+			return
+		}
+		if policy, ok := trigger["policy"].(string); ok && findNamed(realm.Policies, policy) < 0 {
+			writeError(w, http.StatusUnprocessableEntity, "Trigger policy not found")
+			return
+		}
+		realm.Triggers = append(realm.Triggers, trigger)
+		writeData(w, http.StatusCreated, trigger)
+
+	case len(path) == 1:
+		idx := findNamed(realm.Triggers, path[0])
+		if idx < 0 {
+			writeError(w, http.StatusNotFound, "Trigger not found")
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeData(w, http.StatusOK, realm.Triggers[idx])
+		case http.MethodDelete:
+			realm.Triggers = append(realm.Triggers[:idx], realm.Triggers[idx+1:]...)
+			writeNoContent(w)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+
+	default:
+		writeError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+func servePolicies(w http.ResponseWriter, r *http.Request, realm *Realm, path []string) {
+	switch {
+	case len(path) == 0 && r.Method == http.MethodGet:
+		writeData(w, http.StatusOK, names(realm.Policies))
+
+	case len(path) == 0 && r.Method == http.MethodPost:
+		policy := map[string]interface{}{}
+		if err := readData(r, &policy); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		name, _ := policy["name"].(string)
+		if name == "" {
+			writeError(w, http.StatusUnprocessableEntity, "Policy name is missing")
+			return
+		}
+		if handlers, ok := policy["error_handlers"].([]interface{}); !ok || len(handlers) == 0 {
+			writeError(w, http.StatusUnprocessableEntity, "Policy must have at least one error handler")
+			return
+		}
+		if findNamed(realm.Policies, name) >= 0 {
+			writeError(w, http.StatusConflict, "Policy already exists")
+			return
+		}
+		realm.Policies = append(realm.Policies, policy)
+		writeData(w, http.StatusCreated, policy)
+
+	case len(path) == 1:
+		idx := findNamed(realm.Policies, path[0])
+		if idx < 0 {
+			writeError(w, http.StatusNotFound, "Policy not found")
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeData(w, http.StatusOK, realm.Policies[idx])
+		case http.MethodDelete:
+			for _, t := range realm.Triggers {
+				if t["policy"] == path[0] {
+					writeError(w, http.StatusConflict, "Policy is currently being used by one or more triggers")
+					return
+				}
+			}
+			realm.Policies = append(realm.Policies[:idx], realm.Policies[idx+1:]...)
+			writeNoContent(w)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+
+	default:
+		writeError(w, http.StatusNotFound, "Not found")
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mockserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func jsonData(t *testing.T, raw string) interface{} {
+	t.Helper()
+	var data interface{}
+	if err := json.Unmarshal([]byte(raw), &data); err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestRealmManagementInterfaces(t *testing.T) {
+	s := newTestServer(t)
+	iface := func(name string, major, minor int) interface{} {
+		return map[string]interface{}{
+			"interface_name": name, "version_major": major, "version_minor": minor,
+			"type": "properties", "ownership": "server",
+			"mappings": []interface{}{map[string]interface{}{"endpoint": "/value", "type": "string"}},
+		}
+	}
+
+	s.run(t, s.realmToken(t, "a_rma"), []step{
+		{http.MethodGet, "/realmmanagement/v1/test/interfaces", nil, http.StatusOK, `["org.Values"]`},
+		{http.MethodPost, "/realmmanagement/v1/test/interfaces", iface("org.Props", 1, 0), http.StatusCreated, ""},
+		{http.MethodPost, "/realmmanagement/v1/test/interfaces", iface("org.Props", 1, 1), http.StatusConflict, ""},
+		{http.MethodPost, "/realmmanagement/v1/test/interfaces", iface("org.Props", 2, 0), http.StatusCreated, ""},
+		{http.MethodPost, "/realmmanagement/v1/test/interfaces", map[string]interface{}{"interface_name": "org.Invalid"},
+			http.StatusUnprocessableEntity, ""},
+		{http.MethodGet, "/realmmanagement/v1/test/interfaces", nil, http.StatusOK, `["org.Props", "org.Values"]`},
+		{http.MethodGet, "/realmmanagement/v1/test/interfaces/org.Props", nil, http.StatusOK, `[1, 2]`},
+		{http.MethodGet, "/realmmanagement/v1/test/interfaces/org.Missing", nil, http.StatusNotFound, ""},
+		{http.MethodPut, "/realmmanagement/v1/test/interfaces/org.Props/1", iface("org.Props", 1, 0), http.StatusConflict, ""},
+		{http.MethodPut, "/realmmanagement/v1/test/interfaces/org.Props/1", iface("org.Other", 1, 1), http.StatusConflict, ""},
+		{http.MethodPut, "/realmmanagement/v1/test/interfaces/org.Props/1", iface("org.Props", 1, 1), http.StatusNoContent, ""},
+		{http.MethodDelete, "/realmmanagement/v1/test/interfaces/org.Props/1", nil, http.StatusForbidden, ""},
+		{http.MethodDelete, "/realmmanagement/v1/test/interfaces/org.Values/0", nil, http.StatusNoContent, ""},
+		{http.MethodGet, "/realmmanagement/v1/test/interfaces/org.Values/0", nil, http.StatusNotFound, ""},
+	})
+
+	status, data := s.do(t, http.MethodGet, "/realmmanagement/v1/test/interfaces/org.Props/1", s.realmToken(t, "a_rma"), nil)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	got := map[string]interface{}{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["version_minor"] != 1.0 {
+		t.Errorf("version_minor = %v after the update, want 1", got["version_minor"])
+	}
+}
+
+func TestRealmManagementTriggersAndPolicies(t *testing.T) {
+	s := newTestServer(t)
+	policy := jsonData(t, `{"name": "retry", "error_handlers": [{"on": "any_error", "strategy": "retry"}]}`)
+	trigger := func(name, policy string) interface{} {
+		trigger := jsonData(t, `{"action": {"http_url": "https://example.com/hook", "http_method": "post"},
+			"simple_triggers": [{"type": "data_trigger", "on": "incoming_data", "interface_name": "org.Values",
+			"interface_major": 0, "match_path": "/*", "value_match_operator": "*"}]}`).(map[string]interface{})
+		trigger["name"] = name
+		if policy != "" {
+			trigger["policy"] = policy
+		}
+		return trigger
+	}
+
+	s.run(t, s.realmToken(t, "a_rma"), []step{
+		{http.MethodGet, "/realmmanagement/v1/test/policies", nil, http.StatusOK, `[]`},
+		{http.MethodPost, "/realmmanagement/v1/test/policies", jsonData(t, `{"name": "empty", "error_handlers": []}`),
+			http.StatusUnprocessableEntity, ""},
+		{http.MethodPost, "/realmmanagement/v1/test/triggers", trigger("on_value", "retry"), http.StatusUnprocessableEntity, ""},
+		{http.MethodPost, "/realmmanagement/v1/test/policies", policy, http.StatusCreated, ""},
+		{http.MethodPost, "/realmmanagement/v1/test/policies", policy, http.StatusConflict, ""},
+		{http.MethodPost, "/realmmanagement/v1/test/triggers", trigger("on_value", "retry"), http.StatusCreated, ""},
+		{http.MethodPost, "/realmmanagement/v1/test/triggers", trigger("on_value", ""), http.StatusConflict, ""},
+		{http.MethodPost, "/realmmanagement/v1/test/triggers", trigger("", ""), http.StatusUnprocessableEntity, ""},
+		{http.MethodGet, "/realmmanagement/v1/test/triggers", nil, http.StatusOK, `["on_value"]`},
+		{http.MethodGet, "/realmmanagement/v1/test/policies/retry", nil, http.StatusOK,
+			`{"name": "retry", "error_handlers": [{"on": "any_error", "strategy": "retry"}]}`},
+		{http.MethodDelete, "/realmmanagement/v1/test/policies/retry", nil, http.StatusConflict, ""},
+		{http.MethodDelete, "/realmmanagement/v1/test/triggers/on_value", nil, http.StatusNoContent, ""},
+		{http.MethodGet, "/realmmanagement/v1/test/triggers/on_value", nil, http.StatusNotFound, ""},
+		{http.MethodDelete, "/realmmanagement/v1/test/policies/retry", nil, http.StatusNoContent, ""},
+	})
+}
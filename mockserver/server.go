@@ -0,0 +1,241 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mockserver implements an in-memory version of the Astarte APIs used by astartectl:
+// Housekeeping, Realm Management, Pairing and AppEngine. It is meant for offline development
+// and testing: requests are authenticated like Astarte does, validating JWTs against the
+// housekeeping or realm public key, but nothing is persisted.
+//
+// APIs are served under the same prefixes as a real Astarte cluster (e.g. /appengine/v1), so
+// a cluster configuration just needs the base URL of the mock server.
+package mockserver
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/astarte-platform/astarte-go/auth"
+	"github.com/cristalhq/jwt/v3"
+)
+
+// Server is an http.Handler serving the mock Astarte APIs
+type Server struct {
+	mu    sync.Mutex
+	state *State
+
+	housekeepingPublicKeyPEM string
+}
+
+// New returns a Server starting from state. Housekeeping requests are authenticated against
+// housekeepingPublicKeyPEM.
+func New(state *State, housekeepingPublicKeyPEM []byte) (*Server, error) {
+	if _, err := parsePublicKey(string(housekeepingPublicKeyPEM)); err != nil {
+		return nil, fmt.Errorf("invalid housekeeping public key: %w", err)
+	}
+	if state == nil {
+		state = &State{}
+	}
+	return &Server{state: state, housekeepingPublicKeyPEM: string(housekeepingPublicKeyPEM)}, nil
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(segments) == 2 && segments[1] == "health" && r.Method == http.MethodGet {
+		// Health checks are not authenticated, like in Astarte
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if len(segments) < 2 || segments[1] != "v1" {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	api, rest := segments[0], segments[2:]
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if api == "housekeeping" {
+		if status, err := authorize(r, s.housekeepingPublicKeyPEM, "a_ha", strings.Join(rest, "/")); err != nil {
+			writeError(w, status, err.Error())
+			return
+		}
+		s.serveHousekeeping(w, r, rest)
+		return
+	}
+
+	if len(rest) < 1 {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	claim := map[string]string{
+		"realmmanagement": "a_rma",
+		"appengine":       "a_aea",
+		"pairing":         "a_pa",
+	}[api]
+	if claim == "" {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	realm := s.state.realm(rest[0])
+	if realm == nil {
+		// Like Astarte, don't disclose whether the realm exists
+		writeError(w, http.StatusForbidden, "Forbidden")
+		return
+	}
+	rest = rest[1:]
+	if status, err := authorize(r, realm.JwtPublicKeyPEM, claim, strings.Join(rest, "/")); err != nil {
+		writeError(w, status, err.Error())
+		return
+	}
+
+	switch api {
+	case "realmmanagement":
+		s.serveRealmManagement(w, r, realm, rest)
+	case "appengine":
+		s.serveAppEngine(w, r, realm, rest)
+	case "pairing":
+		s.servePairing(w, r, realm, rest)
+	}
+}
+
+// authorize checks the JWT of r against publicKeyPEM, and verifies that the authorization
+// claim allows the request. Claims are "<method regex>::<path regex>", where the path is
+// relative to the realm (or to the API root for housekeeping).
+func authorize(r *http.Request, publicKeyPEM, claimName, relativePath string) (int, error) {
+	rawToken := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if rawToken == "" || rawToken == r.Header.Get("Authorization") {
+		return http.StatusUnauthorized, errors.New("Missing bearer token")
+	}
+	token, err := jwt.ParseString(rawToken)
+	if err != nil {
+		return http.StatusUnauthorized, errors.New("Invalid JWT token")
+	}
+	publicKey, err := parsePublicKey(publicKeyPEM)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	verifier, err := newVerifier(token.Header().Algorithm, publicKey)
+	if err != nil {
+		return http.StatusUnauthorized, err
+	}
+	if err := verifier.Verify(token.Payload(), token.Signature()); err != nil {
+		return http.StatusUnauthorized, errors.New("Invalid JWT token")
+	}
+
+	claims := auth.AstarteClaims{}
+	if err := json.Unmarshal(token.RawClaims(), &claims); err != nil {
+		return http.StatusUnauthorized, errors.New("Invalid JWT token")
+	}
+	if !claims.IsValidExpiresAt(time.Now()) {
+		return http.StatusUnauthorized, errors.New("Expired JWT token")
+	}
+
+	authorizations := map[string][]string{
+		"a_ha":  claims.Housekeeping,
+		"a_rma": claims.RealmManagement,
+		"a_aea": claims.AppEngineAPI,
+		"a_pa":  claims.Pairing,
+	}[claimName]
+	for _, a := range authorizations {
+		parts := strings.SplitN(a, "::", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		methodRegexp, err := regexp.Compile("^(?:" + parts[0] + ")$")
+		if err != nil {
+			continue
+		}
+		pathRegexp, err := regexp.Compile("^(?:" + parts[1] + ")$")
+		if err != nil {
+			continue
+		}
+		if methodRegexp.MatchString(r.Method) && pathRegexp.MatchString(relativePath) {
+			return http.StatusOK, nil
+		}
+	}
+	return http.StatusForbidden, errors.New("Forbidden")
+}
+
+func parsePublicKey(publicKeyPEM string) (interface{}, error) {
+	block, _ := pem.Decode([]byte(publicKeyPEM))
+	if block == nil {
+		return nil, errors.New("public key must be PEM encoded")
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+func newVerifier(algorithm jwt.Algorithm, publicKey interface{}) (jwt.Verifier, error) {
+	switch k := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		return jwt.NewVerifierES(algorithm, k)
+	case *rsa.PublicKey:
+		if strings.HasPrefix(string(algorithm), "PS") {
+			return jwt.NewVerifierPS(algorithm, k)
+		}
+		return jwt.NewVerifierRS(algorithm, k)
+	}
+	return nil, errors.New("Unsupported public key type")
+}
+
+// readData decodes the "data" field of the request body into v
+func readData(r *http.Request, v interface{}) error {
+	body := struct {
+		Data json.RawMessage `json:"data"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return err
+	}
+	if len(body.Data) == 0 {
+		return errors.New("data is missing")
+	}
+	return json.Unmarshal(body.Data, v)
+}
+
+func writeData(w http.ResponseWriter, status int, data interface{}) {
+	writeJSON(w, status, map[string]interface{}{"data": data})
+}
+
+func writeError(w http.ResponseWriter, status int, detail string) {
+	writeJSON(w, status, map[string]interface{}{"errors": map[string]string{"detail": detail}})
+}
+
+func writeNoContent(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// unescape decodes a path segment which was escaped twice, as some clients do with group names
+func unescape(segment string) string {
+	if unescaped, err := url.PathUnescape(segment); err == nil {
+		return unescaped
+	}
+	return segment
+}
@@ -0,0 +1,260 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mockserver
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/astarte-platform/astarte-go/client"
+	"github.com/astarte-platform/astarte-go/interfaces"
+	"github.com/cristalhq/jwt/v3"
+)
+
+const testDeviceID = "2TBn-jNESuuHamE2Zo1anA"
+
+// testServer is a mock server with realm test, which has interface org.Values installed and
+// device testDeviceID registered
+type testServer struct {
+	url             string
+	housekeepingKey *ecdsa.PrivateKey
+	realmKey        *ecdsa.PrivateKey
+}
+
+func newTestKey(t *testing.T) (*ecdsa.PrivateKey, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	public, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: public}))
+}
+
+func newTestServer(t *testing.T) testServer {
+	t.Helper()
+	housekeepingKey, housekeepingPublic := newTestKey(t)
+	realmKey, realmPublic := newTestKey(t)
+
+	iface, err := interfaces.ParseInterface([]byte(`{"interface_name": "org.Values", "version_major": 0,
+		"version_minor": 1, "type": "datastream", "ownership": "device",
+		"mappings": [{"endpoint": "/%{sensor}/value", "type": "double"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	device := &Device{DeviceDetails: client.DeviceDetails{DeviceID: testDeviceID, Connected: true}}
+	device.ensureMaps()
+	device.Introspection["org.Values"] = client.DeviceInterfaceIntrospection{Major: 0, Minor: 1}
+	device.Samples["org.Values"] = map[string][]Sample{
+		"/s1/value": {
+			{"value": 1.0, "timestamp": "2026-01-01T00:00:00Z"},
+			{"value": 2.0, "timestamp": "2026-01-02T00:00:00Z"},
+		},
+	}
+	realm := &Realm{
+		Interfaces: []interfaces.AstarteInterface{interfaces.EnsureInterfaceDefaults(iface)},
+		Devices:    []*Device{device},
+	}
+	realm.Name = "test"
+	realm.JwtPublicKeyPEM = realmPublic
+
+	server, err := New(&State{Realms: []*Realm{realm}}, []byte(housekeepingPublic))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+	return testServer{url: ts.URL, housekeepingKey: housekeepingKey, realmKey: realmKey}
+}
+
+// signToken returns a JWT signed with key, granting authorizations through claim and expiring
+// after ttl
+func signToken(t *testing.T, key *ecdsa.PrivateKey, claim string, authorizations []string, ttl time.Duration) string {
+	t.Helper()
+	signer, err := jwt.NewSignerES(jwt.ES256, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := jwt.NewBuilder(signer).Build(map[string]interface{}{
+		claim: authorizations,
+		"exp": time.Now().Add(ttl).Unix(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token.String()
+}
+
+// do sends a request with the given data and authorization header, and returns the status and
+// the data of the response
+func (s testServer) do(t *testing.T, method, path, authorization string, data interface{}) (int, json.RawMessage) {
+	t.Helper()
+	var body bytes.Buffer
+	if data != nil {
+		if err := json.NewEncoder(&body).Encode(map[string]interface{}{"data": data}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req, err := http.NewRequest(method, s.url+path, &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	response := struct {
+		Data json.RawMessage `json:"data"`
+	}{}
+	_ = json.NewDecoder(res.Body).Decode(&response)
+	return res.StatusCode, response.Data
+}
+
+// realmToken returns a bearer authorization with full access to the realm API guarded by claim
+func (s testServer) realmToken(t *testing.T, claim string) string {
+	return "Bearer " + signToken(t, s.realmKey, claim, []string{".*::.*"}, time.Minute)
+}
+
+func (s testServer) housekeepingToken(t *testing.T) string {
+	return "Bearer " + signToken(t, s.housekeepingKey, "a_ha", []string{".*::.*"}, time.Minute)
+}
+
+// step is a request sent to the mock server, along with the expected response. Data is compared
+// only when wantData is set.
+type step struct {
+	method     string
+	path       string
+	data       interface{}
+	wantStatus int
+	wantData   string
+}
+
+// run sends steps in order, authenticated with authorization, as each one depends on the state
+// left by the previous ones
+func (s testServer) run(t *testing.T, authorization string, steps []step) {
+	t.Helper()
+	for _, st := range steps {
+		status, data := s.do(t, st.method, st.path, authorization, st.data)
+		if status != st.wantStatus {
+			t.Errorf("%s %s: status = %d, want %d", st.method, st.path, status, st.wantStatus)
+			continue
+		}
+		if st.wantData == "" {
+			continue
+		}
+		var got, want interface{}
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Errorf("%s %s: invalid data %q: %s", st.method, st.path, data, err)
+			continue
+		}
+		if err := json.Unmarshal([]byte(st.wantData), &want); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s %s: data = %s, want %s", st.method, st.path, data, st.wantData)
+		}
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	s := newTestServer(t)
+	otherKey, _ := newTestKey(t)
+
+	tests := []struct {
+		name          string
+		authorization string
+		want          int
+	}{
+		{"valid token", "Bearer " + signToken(t, s.realmKey, "a_rma", []string{".*::.*"}, time.Minute), http.StatusOK},
+		{"missing token", "", http.StatusUnauthorized},
+		{"not a bearer token", "Basic dXNlcjpwYXNz", http.StatusUnauthorized},
+		{"malformed token", "Bearer not.a.jwt", http.StatusUnauthorized},
+		{"signed by another key", "Bearer " + signToken(t, otherKey, "a_rma", []string{".*::.*"}, time.Minute), http.StatusUnauthorized},
+		{"signed by the housekeeping key", "Bearer " + signToken(t, s.housekeepingKey, "a_rma", []string{".*::.*"}, time.Minute), http.StatusUnauthorized},
+		{"expired token", "Bearer " + signToken(t, s.realmKey, "a_rma", []string{".*::.*"}, -time.Minute), http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := s.do(t, http.MethodGet, "/realmmanagement/v1/test/interfaces", tt.authorization, nil); got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthorization(t *testing.T) {
+	s := newTestServer(t)
+
+	tests := []struct {
+		name           string
+		key            *ecdsa.PrivateKey
+		claim          string
+		authorizations []string
+		method         string
+		path           string
+		want           int
+	}{
+		{"realm management", s.realmKey, "a_rma", []string{".*::.*"}, http.MethodGet, "/realmmanagement/v1/test/interfaces", http.StatusOK},
+		{"appengine", s.realmKey, "a_aea", []string{".*::.*"}, http.MethodGet, "/appengine/v1/test/devices", http.StatusOK},
+		{"pairing", s.realmKey, "a_pa", []string{".*::.*"}, http.MethodDelete, "/pairing/v1/test/agent/devices/" + testDeviceID, http.StatusNoContent},
+		{"housekeeping", s.housekeepingKey, "a_ha", []string{".*::.*"}, http.MethodGet, "/housekeeping/v1/realms", http.StatusOK},
+		{"claim of another service", s.realmKey, "a_aea", []string{".*::.*"}, http.MethodGet, "/realmmanagement/v1/test/interfaces", http.StatusForbidden},
+		{"realm key on housekeeping", s.realmKey, "a_ha", []string{".*::.*"}, http.MethodGet, "/housekeeping/v1/realms", http.StatusUnauthorized},
+		{"matching method and path", s.realmKey, "a_rma", []string{"GET::interfaces"}, http.MethodGet, "/realmmanagement/v1/test/interfaces", http.StatusOK},
+		{"other method", s.realmKey, "a_rma", []string{"GET::interfaces"}, http.MethodPost, "/realmmanagement/v1/test/interfaces", http.StatusForbidden},
+		{"other path", s.realmKey, "a_rma", []string{"GET::interfaces"}, http.MethodGet, "/realmmanagement/v1/test/triggers", http.StatusForbidden},
+		{"path prefix", s.realmKey, "a_rma", []string{"GET::interface"}, http.MethodGet, "/realmmanagement/v1/test/interfaces", http.StatusForbidden},
+		{"path regexp", s.realmKey, "a_aea", []string{"GET::devices/.*"}, http.MethodGet, "/appengine/v1/test/devices/" + testDeviceID, http.StatusOK},
+		{"invalid regexp", s.realmKey, "a_rma", []string{"GET::(", "GET"}, http.MethodGet, "/realmmanagement/v1/test/interfaces", http.StatusForbidden},
+		{"unknown realm", s.realmKey, "a_rma", []string{".*::.*"}, http.MethodGet, "/realmmanagement/v1/other/interfaces", http.StatusForbidden},
+		{"unknown api", s.realmKey, "a_rma", []string{".*::.*"}, http.MethodGet, "/flow/v1/test/flows", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authorization := "Bearer " + signToken(t, tt.key, tt.claim, tt.authorizations, time.Minute)
+			if got, _ := s.do(t, tt.method, tt.path, authorization, nil); got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHealth(t *testing.T) {
+	s := newTestServer(t)
+	for _, api := range []string{"housekeeping", "realmmanagement", "appengine", "pairing"} {
+		if got, _ := s.do(t, http.MethodGet, "/"+api+"/health", "", nil); got != http.StatusOK {
+			t.Errorf("%s health status = %d, want 200", api, got)
+		}
+	}
+}
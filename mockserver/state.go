@@ -0,0 +1,259 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mockserver
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/astarte-platform/astarte-go/client"
+	"github.com/astarte-platform/astarte-go/interfaces"
+	"gopkg.in/yaml.v3"
+)
+
+// State is the whole content of the mock server. It can be loaded from a seed file, so that
+// tests can start from a known realm layout.
+type State struct {
+	// Realms are the realms of the cluster
+	Realms []*Realm `json:"realms,omitempty"`
+}
+
+// Realm is a realm along with everything installed in it
+type Realm struct {
+	client.RealmDetails
+	// DeviceRegistrationLimit is the maximum number of devices of the realm. Nil when there is no limit
+	DeviceRegistrationLimit *int `json:"device_registration_limit,omitempty"`
+	// DatastreamMaximumStorageRetention is the maximum retention of datastreams, in seconds. Nil when there is no limit
+	DatastreamMaximumStorageRetention *int `json:"datastream_maximum_storage_retention,omitempty"`
+	// Interfaces are the installed interfaces, all major versions included
+	Interfaces []interfaces.AstarteInterface `json:"interfaces,omitempty"`
+	// Triggers are the installed triggers, as accepted by the Realm Management API
+	Triggers []map[string]interface{} `json:"triggers,omitempty"`
+	// Policies are the installed trigger delivery policies, as accepted by the Realm Management API
+	Policies []map[string]interface{} `json:"policies,omitempty"`
+	// Devices are the devices of the realm
+	Devices []*Device `json:"devices,omitempty"`
+}
+
+// Device is a device along with its data
+type Device struct {
+	client.DeviceDetails
+	// Groups are the groups the device belongs to
+	Groups []string `json:"groups,omitempty"`
+	// CredentialsSecret is the secret returned upon registration. Empty when the device is not registered
+	CredentialsSecret string `json:"credentials_secret,omitempty"`
+	// Samples are datastream values, by interface name and path. Individual samples look like
+	// {"value": 42, "timestamp": "..."}, object ones like {"field": 42, "timestamp": "..."}
+	Samples map[string]map[string][]Sample `json:"samples,omitempty"`
+	// Properties are property values, by interface name and path
+	Properties map[string]map[string]interface{} `json:"properties,omitempty"`
+}
+
+// Sample is a single datastream value
+type Sample map[string]interface{}
+
+// Timestamp returns the timestamp of s, or the zero time if it has none
+func (s Sample) Timestamp() time.Time {
+	raw, _ := s["timestamp"].(string)
+	t, _ := time.Parse(time.RFC3339Nano, raw)
+	return t
+}
+
+// LoadState reads a seed file, in YAML or JSON format
+func LoadState(fileName string) (*State, error) {
+	contents, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Go through a generic document, since the structs only know about JSON. YAML 1.2 keeps
+	// the "on" keys of triggers and policies as strings.
+	var document interface{}
+	if err := yaml.Unmarshal(contents, &document); err != nil {
+		return nil, err
+	}
+	marshaled, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+	state := &State{}
+	if err := json.Unmarshal(marshaled, state); err != nil {
+		return nil, err
+	}
+
+	for _, r := range state.Realms {
+		for i := range r.Interfaces {
+			r.Interfaces[i] = interfaces.EnsureInterfaceDefaults(r.Interfaces[i])
+		}
+		for _, d := range r.Devices {
+			d.ensureMaps()
+		}
+	}
+	return state, nil
+}
+
+// realmDetails are the details of a realm returned by Housekeeping, including the settings
+// astarte-go doesn't know about
+type realmDetails struct {
+	client.RealmDetails
+	DeviceRegistrationLimit           *int `json:"device_registration_limit"`
+	DatastreamMaximumStorageRetention *int `json:"datastream_maximum_storage_retention"`
+}
+
+func (r *Realm) details() realmDetails {
+	return realmDetails{
+		RealmDetails:                      r.RealmDetails,
+		DeviceRegistrationLimit:           r.DeviceRegistrationLimit,
+		DatastreamMaximumStorageRetention: r.DatastreamMaximumStorageRetention,
+	}
+}
+
+func (s *State) realm(name string) *Realm {
+	for _, r := range s.Realms {
+		if r.Name == name {
+			return r
+		}
+	}
+	return nil
+}
+
+func (r *Realm) interfaceNames() []string {
+	seen := map[string]bool{}
+	names := []string{}
+	for _, i := range r.Interfaces {
+		if !seen[i.Name] {
+			seen[i.Name] = true
+			names = append(names, i.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (r *Realm) interfaceMajors(name string) []int {
+	majors := []int{}
+	for _, i := range r.Interfaces {
+		if i.Name == name {
+			majors = append(majors, i.MajorVersion)
+		}
+	}
+	sort.Ints(majors)
+	return majors
+}
+
+// findInterface returns the index of an interface, or -1
+func (r *Realm) findInterface(name string, major int) int {
+	for idx, i := range r.Interfaces {
+		if i.Name == name && i.MajorVersion == major {
+			return idx
+		}
+	}
+	return -1
+}
+
+// findNamed returns the index of the trigger or policy called name in items, or -1
+func findNamed(items []map[string]interface{}, name string) int {
+	for idx, i := range items {
+		if i["name"] == name {
+			return idx
+		}
+	}
+	return -1
+}
+
+func names(items []map[string]interface{}) []string {
+	ret := []string{}
+	for _, i := range items {
+		if name, ok := i["name"].(string); ok {
+			ret = append(ret, name)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func (r *Realm) device(deviceID string) *Device {
+	for _, d := range r.Devices {
+		if d.DeviceID == deviceID {
+			return d
+		}
+	}
+	return nil
+}
+
+func (r *Realm) deviceByAlias(alias string) *Device {
+	for _, d := range r.Devices {
+		for _, a := range d.Aliases {
+			if a == alias {
+				return d
+			}
+		}
+	}
+	return nil
+}
+
+func (r *Realm) groupNames() []string {
+	seen := map[string]bool{}
+	groups := []string{}
+	for _, d := range r.Devices {
+		for _, g := range d.Groups {
+			if !seen[g] {
+				seen[g] = true
+				groups = append(groups, g)
+			}
+		}
+	}
+	sort.Strings(groups)
+	return groups
+}
+
+func (r *Realm) groupDevices(group string) []*Device {
+	devices := []*Device{}
+	for _, d := range r.Devices {
+		if d.inGroup(group) {
+			devices = append(devices, d)
+		}
+	}
+	return devices
+}
+
+func (d *Device) inGroup(group string) bool {
+	for _, g := range d.Groups {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *Device) ensureMaps() {
+	if d.Aliases == nil {
+		d.Aliases = map[string]string{}
+	}
+	if d.Attributes == nil {
+		d.Attributes = map[string]string{}
+	}
+	if d.Introspection == nil {
+		d.Introspection = map[string]client.DeviceInterfaceIntrospection{}
+	}
+	if d.Samples == nil {
+		d.Samples = map[string]map[string][]Sample{}
+	}
+	if d.Properties == nil {
+		d.Properties = map[string]map[string]interface{}{}
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package printer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/jedib0t/go-pretty/table"
+	"github.com/spf13/viper"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/yaml"
+)
+
+// Format represents an output format supported by astartectl
+type Format string
+
+const (
+	// TableFormat renders human readable tables. This is the default.
+	TableFormat Format = "table"
+	// CSVFormat renders tables as comma separated values
+	CSVFormat Format = "csv"
+	// JSONFormat renders the result as indented JSON
+	JSONFormat Format = "json"
+	// YAMLFormat renders the result as YAML
+	YAMLFormat Format = "yaml"
+	// JSONPathFormat renders the result of a JSONPath expression evaluated against the JSON representation
+	JSONPathFormat Format = "jsonpath"
+	// GoTemplateFormat renders the result of a Go template executed against the JSON representation
+	GoTemplateFormat Format = "go-template"
+)
+
+// SupportedFormats is the list of values accepted by --output
+var SupportedFormats = []string{"table", "csv", "json", "yaml", "jsonpath=<expression>", "go-template=<template>"}
+
+// Out is where the printer writes its output. It is meant to be changed only when output needs to be captured.
+var Out io.Writer = os.Stdout
+
+// ParseFormat splits an --output value into its Format and, for jsonpath and go-template, its argument.
+func ParseFormat(output string) (Format, string, error) {
+	name, argument, _ := strings.Cut(output, "=")
+	switch Format(name) {
+	case "", "default", TableFormat:
+		return TableFormat, "", nil
+	case CSVFormat, JSONFormat, YAMLFormat:
+		if argument != "" {
+			return "", "", fmt.Errorf("output format %s does not take any argument", name)
+		}
+		return Format(name), "", nil
+	case JSONPathFormat, GoTemplateFormat:
+		if argument == "" {
+			return "", "", fmt.Errorf("output format %s requires an argument, e.g. %s=<expression>", name, name)
+		}
+		return Format(name), argument, nil
+	}
+
+	return "", "", fmt.Errorf("%v is not a supported output type. Supported output types are %v", output, SupportedFormats)
+}
+
+// Validate returns an error if the output format requested through --output is not supported.
+func Validate() error {
+	_, _, err := ParseFormat(viper.GetString("output"))
+	return err
+}
+
+// CurrentFormat returns the Format requested through --output. An invalid value is reported
+// as TableFormat, the error will be returned when trying to print.
+func CurrentFormat() Format {
+	format, _, err := ParseFormat(viper.GetString("output"))
+	if err != nil {
+		return TableFormat
+	}
+	return format
+}
+
+// IsStructured returns true when the requested output is meant to be consumed by machines rather than humans,
+// i.e. when the raw object rather than its tabular representation will be printed.
+func IsStructured() bool {
+	switch CurrentFormat() {
+	case TableFormat, CSVFormat:
+		return false
+	}
+	return true
+}
+
+// NewTable returns a table.Writer styled according to the requested output format.
+func NewTable() table.Writer {
+	t := table.NewWriter()
+	t.SetOutputMirror(Out)
+	if CurrentFormat() == TableFormat {
+		t.SetStyle(table.StyleLight)
+	}
+	return t
+}
+
+// Print renders obj in the requested structured format. When a human readable format was requested,
+// humanReadable is called instead and it is in charge of printing obj.
+func Print(obj interface{}, humanReadable func()) error {
+	format, argument, err := ParseFormat(viper.GetString("output"))
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case TableFormat, CSVFormat:
+		humanReadable()
+		return nil
+	case JSONFormat:
+		marshaledOutput, err := json.MarshalIndent(obj, "", "    ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(Out, string(marshaledOutput))
+	case YAMLFormat:
+		marshaledOutput, err := yaml.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(Out, string(marshaledOutput))
+	case JSONPathFormat:
+		return printJSONPath(obj, argument)
+	case GoTemplateFormat:
+		return printGoTemplate(obj, argument)
+	}
+	return nil
+}
+
+// PrintTable renders t when a table or csv output was requested, and obj otherwise.
+func PrintTable(t table.Writer, obj interface{}) error {
+	return Print(obj, func() {
+		if CurrentFormat() == CSVFormat {
+			t.RenderCSV()
+		} else {
+			t.Render()
+		}
+	})
+}
+
+// PrintList renders a list of names as a single column table with the given header.
+func PrintList(header string, items []string) error {
+	t := NewTable()
+	t.AppendHeader(table.Row{header})
+	for _, i := range items {
+		t.AppendRow(table.Row{i})
+	}
+	return PrintTable(t, items)
+}
+
+func printJSONPath(obj interface{}, expression string) error {
+	data, err := toGenericJSON(obj)
+	if err != nil {
+		return err
+	}
+
+	j := jsonpath.New("output")
+	// Allow users to pass either {.field} or .field, as kubectl does
+	if !strings.HasPrefix(expression, "{") {
+		expression = "{" + expression + "}"
+	}
+	if err := j.Parse(expression); err != nil {
+		return fmt.Errorf("invalid jsonpath expression: %w", err)
+	}
+	if err := j.Execute(Out, data); err != nil {
+		return err
+	}
+	fmt.Fprintln(Out)
+	return nil
+}
+
+func printGoTemplate(obj interface{}, text string) error {
+	data, err := toGenericJSON(obj)
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := template.New("output").Parse(text)
+	if err != nil {
+		return fmt.Errorf("invalid go-template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return err
+	}
+	fmt.Fprint(Out, buf.String())
+	return nil
+}
+
+// toGenericJSON converts obj to its JSON representation made of maps and slices, so that
+// jsonpath and templates can refer to fields by their JSON name.
+func toGenericJSON(obj interface{}) (interface{}, error) {
+	marshaled, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var ret interface{}
+	if err := json.Unmarshal(marshaled, &ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
@@ -0,0 +1,175 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/user"
+	"strings"
+	"time"
+
+	"github.com/astarte-platform/astartectl/config"
+	"github.com/spf13/viper"
+)
+
+// auditTransport is an http.RoundTripper recording every request changing the state of Astarte,
+// and its result, in the audit log. Safe requests are not recorded.
+type auditTransport struct {
+	base    http.RoundTripper
+	logFile string
+}
+
+func newAuditTransport(base http.RoundTripper, logFile string) http.RoundTripper {
+	return &auditTransport{
+		base:    base,
+		logFile: logFile,
+	}
+}
+
+func (t *auditTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return t.base.RoundTrip(req)
+	}
+
+	realm, resource := auditTarget(req)
+	entry := config.AuditEntry{
+		Time:     time.Now().UTC(),
+		User:     currentUsername(),
+		Context:  config.CurrentContextName(),
+		Realm:    realm,
+		Command:  strings.Join(redactCommandLine(os.Args), " "),
+		Method:   req.Method,
+		URL:      req.URL.Redacted(),
+		Resource: resource,
+	}
+
+	res, err := t.base.RoundTrip(req)
+	if err != nil {
+		entry.Error = err.Error()
+	} else {
+		entry.Status = res.StatusCode
+	}
+	// Never fail a request which was already sent because it could not be recorded
+	if err := config.AppendAuditEntry(t.logFile, entry); err != nil {
+		fmt.Fprintf(os.Stderr, "warn: Could not write to the audit log: %s\n", err)
+	}
+	return res, err
+}
+
+// auditCollections maps the collections where resources are created by POSTing them to the field of
+// the request body naming the new resource
+var auditCollections = map[string]string{
+	"interfaces":    "interface_name",
+	"triggers":      "name",
+	"policies":      "name",
+	"groups":        "group_name",
+	"agent/devices": "hw_id",
+	"realms":        "realm_name",
+}
+
+// auditTarget returns the realm and the resource targeted by req, from URL paths such as
+// /appengine/v1/<realm>/devices/<device_id> or /housekeeping/v1/realms/<realm>. When a resource is
+// created in a collection, its name is taken from the request body.
+func auditTarget(req *http.Request) (realm, resource string) {
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	for i, s := range segments {
+		if s == "v1" {
+			segments = segments[i+1:]
+			break
+		}
+	}
+	if len(segments) == 0 {
+		return viper.GetString("realm.name"), req.URL.Path
+	}
+
+	switch {
+	case segments[0] != "realms":
+		realm, resource = segments[0], strings.Join(segments[1:], "/")
+	case len(segments) > 1:
+		// Housekeeping: the realm is in the path, or in the body when creating it
+		return segments[1], strings.Join(segments, "/")
+	default:
+		resource = "realms"
+	}
+
+	nameField, ok := auditCollections[resource]
+	if !ok || req.Method != http.MethodPost {
+		return realm, resource
+	}
+	name := auditBodyField(req, nameField)
+	if resource == "realms" {
+		realm = name
+	}
+	if name != "" {
+		resource += "/" + name
+	}
+	return realm, resource
+}
+
+// auditBodyField returns the string field of the data in the body of req, or "" if it has none
+func auditBodyField(req *http.Request, field string) string {
+	if req.GetBody == nil {
+		return ""
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+	payload := struct {
+		Data map[string]interface{} `json:"data"`
+	}{}
+	raw, _ := io.ReadAll(body)
+	if json.Unmarshal(raw, &payload) != nil {
+		return ""
+	}
+	value, _ := payload.Data[field].(string)
+	return value
+}
+
+func currentUsername() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+// redactCommandLine returns args with the value of --token replaced
+func redactCommandLine(args []string) []string {
+	redacted := make([]string, 0, len(args))
+	redactNext := false
+	for _, arg := range args {
+		switch {
+		case redactNext:
+			arg = redactedValue
+			redactNext = false
+		case arg == "-t" || arg == "--token":
+			redactNext = true
+		case strings.HasPrefix(arg, "--token="):
+			arg = "--token=" + redactedValue
+		case strings.HasPrefix(arg, "-t="):
+			arg = "-t=" + redactedValue
+		case strings.HasPrefix(arg, "-t") && !strings.HasPrefix(arg, "--"):
+			arg = "-t" + redactedValue
+		}
+		redacted = append(redacted, arg)
+	}
+	return redacted
+}
@@ -16,31 +16,63 @@ package utils
 
 import (
 	"crypto/tls"
-	"encoding/base64"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"net/http"
+	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/astarte-platform/astarte-go/astarteservices"
+	"github.com/astarte-platform/astarte-go/auth"
 	"github.com/astarte-platform/astarte-go/client"
+	"github.com/astarte-platform/astartectl/config"
 	"github.com/spf13/viper"
 )
 
+// requestTimeout is the maximum amount of time a single attempt of an API request can take
+const requestTimeout = 30 * time.Second
+
 // APICommandSetup is a helper for setting up a generic command using Astarte API.
 // individualURLs must contain the service->variable association.
 func APICommandSetup(individualURLVariables map[astarteservices.AstarteService]string, keyVariable, keyFileVariable string) (*client.Client, error) {
-	var clientConfig = []client.Option{}
+	services := []astarteservices.AstarteService{}
+	for s := range individualURLVariables {
+		services = append(services, s)
+	}
+	credentials, err := setupAuth(services, keyVariable, keyFileVariable)
+	if err != nil {
+		return nil, err
+	}
+	return newAPIClient(individualURLVariables, credentials)
+}
 
-	httpConfig := setupHTTP()
-	clientConfig = append(clientConfig, httpConfig...)
+// APIClientWithKey returns a client for the same APIs as APICommandSetup, authenticating with
+// privateKey instead of the configured credentials
+func APIClientWithKey(individualURLVariables map[astarteservices.AstarteService]string, privateKey []byte) (*client.Client, error) {
+	if _, err := auth.ParsePrivateKeyFromPEM(privateKey); err != nil {
+		return nil, err
+	}
+	return newAPIClient(individualURLVariables, apiCredentials{privateKey: privateKey})
+}
 
-	authConfig, err := setupAuth(keyVariable, keyFileVariable)
+// APIClientWithToken returns a client for the same APIs as APICommandSetup, authenticating with
+// token instead of the configured credentials
+func APIClientWithToken(individualURLVariables map[astarteservices.AstarteService]string, token string) (*client.Client, error) {
+	return newAPIClient(individualURLVariables, apiCredentials{token: token})
+}
+
+func newAPIClient(individualURLVariables map[astarteservices.AstarteService]string, credentials apiCredentials) (*client.Client, error) {
+	var clientConfig = []client.Option{}
+
+	httpClient, err := setupHTTP()
 	if err != nil {
 		return nil, err
 	}
-	clientConfig = append(clientConfig, authConfig...)
+	clientConfig = append(clientConfig, client.WithHTTPClient(httpClient))
+	clientConfig = append(clientConfig, credentials.options()...)
 
 	URLConfig, err := setupURLs(individualURLVariables)
 	if err != nil {
@@ -53,51 +85,199 @@ func APICommandSetup(individualURLVariables map[astarteservices.AstarteService]s
 		return nil, err
 	}
 
+	// Keep what is needed to run raw requests with the same client
+	rawClientsLock.Lock()
+	defer rawClientsLock.Unlock()
+	rawClients[astarteAPIClient] = rawClient{httpClient: httpClient, credentials: credentials}
 	return astarteAPIClient, nil
 }
 
-func setupHTTP() []client.Option {
-	var ret = []client.Option{}
-	ignoreSSLErrors := viper.GetBool("ignore-ssl-errors")
-	if ignoreSSLErrors {
-		httpClient := &http.Client{
-			Timeout: time.Second * 30,
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{
-					InsecureSkipVerify: ignoreSSLErrors,
-				},
-			},
-		}
-		ret = append(ret, client.WithHTTPClient(httpClient))
+// apiCredentials are the credentials an API client authenticates with: either a token, or a
+// private key minting short-lived tokens
+type apiCredentials struct {
+	token      string
+	privateKey []byte
+}
+
+func (c apiCredentials) options() []client.Option {
+	if c.token != "" {
+		return []client.Option{client.WithJWT(c.token)}
 	}
-	return ret
+	// 1 minute TTL is more than enough for our purposes
+	return []client.Option{client.WithPrivateKey(c.privateKey), client.WithExpiry(60)}
 }
 
-func setupAuth(keyVariable, keyFileVariable string) ([]client.Option, error) {
-	var ret = []client.Option{}
+// jwt returns a token valid for all APIs, as the client does
+func (c apiCredentials) jwt() (string, error) {
+	if c.token != "" {
+		return c.token, nil
+	}
+	servicesAndClaims := map[astarteservices.AstarteService][]string{
+		astarteservices.AppEngine:       {},
+		astarteservices.Channels:        {},
+		astarteservices.Flow:            {},
+		astarteservices.Housekeeping:    {},
+		astarteservices.Pairing:         {},
+		astarteservices.RealmManagement: {},
+	}
+	return auth.GenerateAstarteJWTFromPEMKey(c.privateKey, servicesAndClaims, 60)
+}
+
+func setupHTTP() (*http.Client, error) {
+	var transport http.RoundTripper
+	transport, err := sharedTransport()
+	if err != nil {
+		return nil, err
+	}
+	if trace := viper.GetBool("trace"); trace || viper.GetBool("verbose") {
+		// Trace every single attempt, hence install it below the retry transport
+		transport = newTracingTransport(transport, trace)
+	}
+
+	// Each attempt gets its own time budget, instead of a timeout on the whole http.Client
+	transport = newRetryTransport(transport, viper.GetInt("retry.max-attempts"), viper.GetDuration("retry.backoff"),
+		viper.GetDuration("retry.max-backoff"), requestTimeout)
+	// A request is recorded once, whatever the number of attempts
+	transport = newAuditTransport(transport, config.GetAuditLogPath())
+	if IsDryRun() {
+		// Above the retry transport, as a request which was not sent must not be retried
+		transport = newDryRunTransport(transport)
+	}
+
+	httpClient := &http.Client{
+		Transport: transport,
+	}
+	return httpClient, nil
+}
+
+var (
+	transportOnce sync.Once
+	transport     *http.Transport
+	transportErr  error
+)
+
+// sharedTransport returns the transport used by all API clients. It is built only once,
+// so that connections are reused across services.
+func sharedTransport() (*http.Transport, error) {
+	transportOnce.Do(func() {
+		var tlsConfig *tls.Config
+		tlsConfig, transportErr = setupTLS()
+		if transportErr != nil {
+			return
+		}
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+	})
+	return transport, transportErr
+}
 
+func setupTLS() (*tls.Config, error) {
+	return NewTLSConfig(config.TLSConfiguration{
+		CAFile:     viper.GetString("tls.ca-file"),
+		ClientCert: viper.GetString("tls.client-cert"),
+		ClientKey:  viper.GetString("tls.client-key"),
+	}, viper.GetBool("ignore-ssl-errors"))
+}
+
+// NewTLSConfig returns the TLS configuration for connecting to a cluster with tlsConfiguration,
+// loading its CA bundle and client certificate
+func NewTLSConfig(tlsConfiguration config.TLSConfiguration, insecureSkipVerify bool) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: insecureSkipVerify,
+	}
+
+	if caFile := tlsConfiguration.CAFile; caFile != "" {
+		caPEM, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read CA file: %w", err)
+		}
+		// Trust the system CAs too, a CA bundle usually just adds an internal PKI
+		certPool, err := x509.SystemCertPool()
+		if err != nil {
+			certPool = x509.NewCertPool()
+		}
+		if !certPool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no valid PEM certificate found in %s", caFile)
+		}
+		tlsConfig.RootCAs = certPool
+	}
+
+	clientCert := tlsConfiguration.ClientCert
+	clientKey := tlsConfiguration.ClientKey
+	if clientCert != "" || clientKey != "" {
+		if clientCert == "" || clientKey == "" {
+			return nil, errors.New("client-cert and client-key have to be specified together")
+		}
+		certificate, err := tls.LoadX509KeyPair(clientCert, clientKey)
+		if err != nil {
+			return nil, fmt.Errorf("could not load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return tlsConfig, nil
+}
+
+func setupAuth(services []astarteservices.AstarteService, keyVariable, keyFileVariable string) (apiCredentials, error) {
 	// Setup auth
 	privateKeyFile := viper.GetString(keyFileVariable)
 	privateKey := viper.GetString(keyVariable)
 	explicitToken := viper.GetString("token")
 	if privateKey == "" && privateKeyFile == "" && explicitToken == "" {
-		return nil, fmt.Errorf("%s or token is required", strings.Replace(keyFileVariable, ".", "-", -1))
-	}
-	if explicitToken == "" {
-		// 1 minute TTL is more than enough for our purposes
-		if privateKeyFile != "" {
-			ret = append(ret, client.WithPrivateKey(privateKeyFile), client.WithExpiry(60))
-		} else {
-			decoded, err := base64.StdEncoding.DecodeString(privateKey)
-			if err != nil {
-				return nil, err
-			}
-			ret = append(ret, client.WithPrivateKey(decoded), client.WithExpiry(60))
-		}
+		// Fall back to credential commands, which are run only when no other credential is available
+		return setupAuthFromCommands(services, strings.TrimSuffix(keyVariable, ".key"), keyFileVariable)
+	}
+	if explicitToken != "" {
+		return apiCredentials{token: explicitToken}, nil
+	}
+
+	var decoded []byte
+	var err error
+	if privateKeyFile != "" {
+		decoded, err = os.ReadFile(privateKeyFile)
 	} else {
-		ret = append(ret, client.WithJWT(explicitToken))
+		decoded, err = config.DecodeKey(privateKey)
 	}
-	return ret, nil
+	if err != nil {
+		return apiCredentials{}, err
+	}
+	return setupPrivateKeyAuth(services, decoded)
+}
+
+func setupAuthFromCommands(services []astarteservices.AstarteService, configPrefix, keyFileVariable string) (apiCredentials, error) {
+	if tokenCommand := execCredentialConfigurationFromViper(configPrefix + ".token-command"); tokenCommand != nil {
+		token, err := tokenFromCommand(tokenCommand)
+		if err != nil {
+			return apiCredentials{}, err
+		}
+		return apiCredentials{token: token}, nil
+	}
+
+	if keyCommand := execCredentialConfigurationFromViper(configPrefix + ".key-command"); keyCommand != nil {
+		key, err := keyFromCommand(keyCommand)
+		if err != nil {
+			return apiCredentials{}, err
+		}
+		return setupPrivateKeyAuth(services, key)
+	}
+
+	return apiCredentials{}, fmt.Errorf("%s or token is required", strings.Replace(keyFileVariable, ".", "-", -1))
+}
+
+func setupPrivateKeyAuth(services []astarteservices.AstarteService, privateKey []byte) (apiCredentials, error) {
+	if !viper.GetBool("token-cache") {
+		return apiCredentials{privateKey: privateKey}, nil
+	}
+
+	if !includesHousekeeping(services) {
+		// The client may call any realm API, like the tokens it signs with the key
+		services = RealmServices
+	}
+	token, err := CachedToken(config.CurrentContextName(), privateKey, services)
+	if err != nil {
+		return apiCredentials{}, err
+	}
+	return apiCredentials{token: token}, nil
 }
 
 func setupURLs(individualURLVariables map[astarteservices.AstarteService]string) ([]client.Option, error) {
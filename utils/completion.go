@@ -0,0 +1,221 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/astarte-platform/astarte-go/client"
+	"github.com/astarte-platform/astartectl/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// completionCacheTTL is how long completion candidates fetched from Astarte are reused
+const completionCacheTTL = time.Minute
+
+// MaxCompletedDevices caps the devices fetched for completion, which must stay fast in large realms
+const MaxCompletedDevices = 1000
+
+// Completer returns the completion candidates for the positional argument following args
+type Completer func(args []string) ([]string, error)
+
+type cachedCompletion struct {
+	Candidates []string  `json:"candidates"`
+	Expiration time.Time `json:"expiration"`
+}
+
+// CompleteArgs returns a cobra ValidArgsFunction completing each positional argument with the
+// Completer in the same position. A nil Completer, or a missing one, completes nothing.
+// Completers run after the persistent pre-run hook of the command, so they can use its API client.
+// Their candidates are cached on disk for a short time, so that completion stays fast.
+func CompleteArgs(completers ...Completer) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) >= len(completers) || completers[len(args)] == nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		if err := loadCompletionConfiguration(cmd); err != nil {
+			cobra.CompErrorln(err.Error())
+			return nil, cobra.ShellCompDirectiveError
+		}
+		cachePath := completionCachePath(cmd, args)
+		if cached, err := loadCachedCompletion(cachePath); err == nil {
+			return filterCandidates(cached, toComplete), cobra.ShellCompDirectiveNoFileComp
+		}
+
+		if err := runPersistentPreRun(cmd, args); err != nil {
+			cobra.CompErrorln(err.Error())
+			return nil, cobra.ShellCompDirectiveError
+		}
+		candidates, err := completers[len(args)](args)
+		if err != nil {
+			cobra.CompErrorln(err.Error())
+			return nil, cobra.ShellCompDirectiveError
+		}
+		// Failing to cache candidates is not fatal, they will be fetched again next time
+		_ = saveCachedCompletion(cachePath, candidates)
+		return filterCandidates(candidates, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// RequestCandidates returns a function running a request listing names or versions, and returning
+// them as completion candidates. It is meant to wrap the functions building requests, such as
+// RequestCandidates(c)(c.ListInterfaces(realm)).
+func RequestCandidates(c *client.Client) func(client.AstarteRequest, error) ([]string, error) {
+	return func(req client.AstarteRequest, err error) ([]string, error) {
+		if err != nil {
+			return nil, err
+		}
+		res, err := req.Run(c)
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := res.Parse()
+		if err != nil {
+			return nil, err
+		}
+
+		switch list := parsed.(type) {
+		case []string:
+			return list, nil
+		case []int:
+			candidates := []string{}
+			for _, i := range list {
+				candidates = append(candidates, strconv.Itoa(i))
+			}
+			return candidates, nil
+		default:
+			return nil, fmt.Errorf("unexpected response %v", parsed)
+		}
+	}
+}
+
+// CompleteContexts is a cobra completion function for context names
+func CompleteContexts(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	contexts, err := config.ListContextConfigurations(config.GetConfigDir())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return filterCandidates(contexts, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// CompleteClusters is a cobra completion function for cluster names
+func CompleteClusters(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	clusters, err := config.ListClusterConfigurations(config.GetConfigDir())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return filterCandidates(clusters, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// CompleteFirstArg wraps a completion function so that it completes only the first positional argument
+func CompleteFirstArg(complete func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective)) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return complete(cmd, args, toComplete)
+	}
+}
+
+// loadCompletionConfiguration loads the context requested with --context, as cobra doesn't parse
+// flags before initializing the configuration when completing
+func loadCompletionConfiguration(cmd *cobra.Command) error {
+	contextName, _ := cmd.Flags().GetString("context")
+	if contextName == "" {
+		return nil
+	}
+	return config.ConfigureViper(contextName)
+}
+
+// runPersistentPreRun runs the persistent pre-run hook of cmd, which cobra doesn't run when completing
+func runPersistentPreRun(cmd *cobra.Command, args []string) error {
+	// Completion runs in the background of the shell, it must never prompt
+	if devNull, err := os.Open(os.DevNull); err == nil {
+		os.Stdin = devNull
+	}
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.PersistentPreRunE != nil {
+			return c.PersistentPreRunE(cmd, args)
+		}
+	}
+	return nil
+}
+
+// completionCachePath returns the cache file of the candidates for the argument following args,
+// keyed by everything identifying the realm they come from
+func completionCachePath(cmd *cobra.Command, args []string) string {
+	realm, _ := cmd.Flags().GetString("realm-name")
+	if realm == "" {
+		realm = viper.GetString("realm.name")
+	}
+	url, _ := cmd.Flags().GetString("astarte-url")
+	if url == "" {
+		url = viper.GetString("url")
+	}
+
+	hash := sha256.New()
+	for _, part := range append([]string{config.CurrentContextName(), url, realm, cmd.CommandPath()}, args...) {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return path.Join(config.GetCacheDir(), "completion", hex.EncodeToString(hash.Sum(nil))+".json")
+}
+
+func loadCachedCompletion(cachePath string) ([]string, error) {
+	contents, err := os.ReadFile(cachePath)
+	if err != nil {
+		return nil, err
+	}
+	cached := cachedCompletion{}
+	if err := json.Unmarshal(contents, &cached); err != nil {
+		return nil, err
+	}
+	if time.Now().After(cached.Expiration) {
+		return nil, errors.New("cached completion expired")
+	}
+	return cached.Candidates, nil
+}
+
+func saveCachedCompletion(cachePath string, candidates []string) error {
+	if err := os.MkdirAll(path.Dir(cachePath), 0700); err != nil {
+		return err
+	}
+	contents, err := json.Marshal(cachedCompletion{Candidates: candidates, Expiration: time.Now().Add(completionCacheTTL)})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(cachePath, contents, 0600)
+}
+
+func filterCandidates(candidates []string, toComplete string) []string {
+	filtered := []string{}
+	for _, c := range candidates {
+		if strings.HasPrefix(c, toComplete) {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,192 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/astarte-platform/astartectl/config"
+	"github.com/spf13/viper"
+)
+
+// credentialExpirySkew is subtracted from a token's expiration, so that a cached token
+// won't expire while a command is running
+const credentialExpirySkew = 30 * time.Second
+
+// execCredential is the output of a credential command
+type execCredential struct {
+	// Token is a JWT to be used as is
+	Token string `json:"token,omitempty"`
+	// Key is a PEM encoded private key
+	Key string `json:"key,omitempty"`
+	// Expiration is when Token expires. When omitted, it is read from the token's claims
+	Expiration *time.Time `json:"expiration,omitempty"`
+}
+
+// execCredentialConfigurationFromViper returns the credential command configured under key, if any
+func execCredentialConfigurationFromViper(key string) *config.ExecCredentialConfiguration {
+	command := viper.GetString(key + ".command")
+	if command == "" {
+		return nil
+	}
+	return &config.ExecCredentialConfiguration{
+		Command: command,
+		Args:    viper.GetStringSlice(key + ".args"),
+		Env:     viper.GetStringMapString(key + ".env"),
+	}
+}
+
+// tokenFromCommand returns a token provided by the given command. Tokens are cached on disk
+// until they expire, so that the command is not run on every invocation.
+func tokenFromCommand(command *config.ExecCredentialConfiguration) (string, error) {
+	cachePath := credentialCachePath(command)
+	if cached, err := loadCachedCredential(cachePath); err == nil {
+		return cached.Token, nil
+	}
+
+	credential, err := runCredentialCommand(command)
+	if err != nil {
+		return "", err
+	}
+	if credential.Token == "" {
+		return "", fmt.Errorf("%s did not provide a token", command.Command)
+	}
+
+	if credential.Expiration == nil {
+		credential.Expiration = jwtExpiration(credential.Token)
+	}
+	if credential.Expiration != nil {
+		// Failing to cache the token is not fatal, the command will just run again next time
+		_ = saveCachedCredential(cachePath, credential)
+	}
+	return credential.Token, nil
+}
+
+// keyFromCommand returns a PEM private key provided by the given command. Keys are never
+// written to disk.
+func keyFromCommand(command *config.ExecCredentialConfiguration) ([]byte, error) {
+	credential, err := runCredentialCommand(command)
+	if err != nil {
+		return nil, err
+	}
+	if credential.Key == "" {
+		return nil, fmt.Errorf("%s did not provide a key", command.Command)
+	}
+	return []byte(credential.Key), nil
+}
+
+func runCredentialCommand(command *config.ExecCredentialConfiguration) (execCredential, error) {
+	credential := execCredential{}
+
+	cmd := exec.Command(command.Command, command.Args...)
+	cmd.Env = os.Environ()
+	for k, v := range command.Env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	// Let the command interact with the user, e.g. for an SSO login
+	cmd.Stdin = os.Stdin
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return credential, fmt.Errorf("error while running credential command %s: %w", command.Command, err)
+	}
+
+	output = bytes.TrimSpace(output)
+	if bytes.HasPrefix(output, []byte("-----BEGIN")) {
+		credential.Key = string(output)
+		return credential, nil
+	}
+	if err := json.Unmarshal(output, &credential); err != nil {
+		return credential, fmt.Errorf("credential command %s returned invalid output: %w", command.Command, err)
+	}
+	return credential, nil
+}
+
+// credentialCachePath returns a cache file path unique to the command, its arguments and environment
+func credentialCachePath(command *config.ExecCredentialConfiguration) string {
+	h := sha256.New()
+	fmt.Fprintln(h, command.Command)
+	for _, a := range command.Args {
+		fmt.Fprintln(h, a)
+	}
+	envKeys := make([]string, 0, len(command.Env))
+	for k := range command.Env {
+		envKeys = append(envKeys, k)
+	}
+	sort.Strings(envKeys)
+	for _, k := range envKeys {
+		fmt.Fprintf(h, "%s=%s\n", k, command.Env[k])
+	}
+	return path.Join(config.GetCacheDir(), "credentials", hex.EncodeToString(h.Sum(nil))+".json")
+}
+
+func loadCachedCredential(cachePath string) (execCredential, error) {
+	credential := execCredential{}
+	contents, err := os.ReadFile(cachePath)
+	if err != nil {
+		return credential, err
+	}
+	if err := json.Unmarshal(contents, &credential); err != nil {
+		return credential, err
+	}
+	if credential.Token == "" || credential.Expiration == nil || time.Now().Add(credentialExpirySkew).After(*credential.Expiration) {
+		return credential, errors.New("cached credential expired")
+	}
+	return credential, nil
+}
+
+func saveCachedCredential(cachePath string, credential execCredential) error {
+	if err := os.MkdirAll(path.Dir(cachePath), 0700); err != nil {
+		return err
+	}
+	contents, err := json.Marshal(credential)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(cachePath, contents, 0600)
+}
+
+// jwtExpiration returns the expiration of a JWT, read from its exp claim without verifying it.
+// It returns nil if the token has no expiration or it can't be parsed.
+func jwtExpiration(token string) *time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil
+	}
+	claims := struct {
+		Exp int64 `json:"exp"`
+	}{}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return nil
+	}
+	expiration := time.Unix(claims.Exp, 0)
+	return &expiration
+}
@@ -0,0 +1,109 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/astarte-platform/astarte-go/client"
+	"github.com/astarte-platform/astartectl/printer"
+	"github.com/spf13/viper"
+)
+
+// DryRunError is returned when a request changing the state of Astarte is not sent
+// because of --dry-run. It describes the request that would have been sent.
+type DryRunError struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Body   interface{} `json:"body,omitempty"`
+}
+
+func (e *DryRunError) Error() string {
+	return fmt.Sprintf("dry run: %s %s was not sent", e.Method, e.URL)
+}
+
+// IsDryRun returns true if --dry-run was given
+func IsDryRun() bool {
+	return viper.GetBool("dry-run")
+}
+
+// MaybeDryRunAndExit prints the description of req and exits if --dry-run was given,
+// without sending it. It must be called only right before running a request changing
+// the state of Astarte: other requests are sent to Astarte as usual.
+func MaybeDryRunAndExit(req client.AstarteRequest, c *client.Client) {
+	if !IsDryRun() {
+		return
+	}
+	if _, err := req.Run(c); err != nil {
+		if err := PrintDryRun(err); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+}
+
+// PrintDryRun prints the request described by err if it was not sent because of --dry-run,
+// honoring --output. Any other error is returned unchanged.
+func PrintDryRun(err error) error {
+	var dryRunErr *DryRunError
+	if !errors.As(err, &dryRunErr) {
+		return err
+	}
+	return printer.Print(dryRunErr, func() {
+		fmt.Fprintf(printer.Out, "Dry run, not sending: %s %s\n", dryRunErr.Method, dryRunErr.URL)
+		if dryRunErr.Body != nil {
+			body, _ := json.MarshalIndent(dryRunErr.Body, "", "    ")
+			fmt.Fprintln(printer.Out, string(body))
+		}
+	})
+}
+
+// dryRunTransport is an http.RoundTripper which never sends requests changing the state of
+// Astarte, returning a *DryRunError instead. Safe requests are sent as usual.
+type dryRunTransport struct {
+	base http.RoundTripper
+}
+
+func newDryRunTransport(base http.RoundTripper) http.RoundTripper {
+	return &dryRunTransport{base: base}
+}
+
+func (t *dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return t.base.RoundTrip(req)
+	}
+
+	dryRunErr := &DryRunError{Method: req.Method, URL: req.URL.Redacted()}
+	if req.Body != nil && req.Body != http.NoBody {
+		raw, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		var body interface{}
+		if err := json.Unmarshal(raw, &body); err != nil {
+			body = string(raw)
+		}
+		dryRunErr.Body = body
+	}
+	return nil, dryRunErr
+}
@@ -0,0 +1,60 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func init() {
+	cobra.AddTemplateFunc("visibleInheritedFlags", visibleInheritedFlags)
+}
+
+// AddOutputFileFlag adds the --output-file flag to cmd. Its former name, --output/-o, is kept as a
+// deprecated alias. It shadows the global --output flag selecting the output format, which is
+// left out of the help of cmd.
+func AddOutputFileFlag(cmd *cobra.Command, usage string) {
+	cmd.Flags().String("output-file", "", usage)
+	cmd.Flags().StringP("output", "o", "", usage)
+	_ = cmd.Flags().MarkDeprecated("output", "use --output-file instead")
+
+	// Cobra lists inherited flags even when a local flag with the same name takes precedence
+	cmd.SetUsageTemplate(strings.ReplaceAll(cmd.UsageTemplate(), ".InheritedFlags.FlagUsages", "(visibleInheritedFlags .).FlagUsages"))
+}
+
+// visibleInheritedFlags returns the flags cmd inherits from its parents, except the ones shadowed
+// by its own flags
+func visibleInheritedFlags(cmd *cobra.Command) *pflag.FlagSet {
+	flags := pflag.NewFlagSet(cmd.Name(), pflag.ContinueOnError)
+	cmd.InheritedFlags().VisitAll(func(f *pflag.Flag) {
+		if cmd.Flags().Lookup(f.Name) == f {
+			flags.AddFlag(f)
+		}
+	})
+	return flags
+}
+
+// GetOutputFile returns the value of the --output-file flag added by AddOutputFileFlag, or of its
+// deprecated alias
+func GetOutputFile(flags *pflag.FlagSet) (string, error) {
+	outputFile, err := flags.GetString("output-file")
+	if err != nil || outputFile != "" {
+		return outputFile, err
+	}
+	return flags.GetString("output")
+}
@@ -0,0 +1,153 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/astarte-platform/astarte-go/client"
+	"moul.io/http2curl"
+)
+
+// rawClient holds what a client built by APICommandSetup needs to run raw requests
+type rawClient struct {
+	httpClient  *http.Client
+	credentials apiCredentials
+}
+
+var (
+	rawClientsLock sync.Mutex
+	rawClients     = map[*client.Client]rawClient{}
+)
+
+func rawClientFor(c *client.Client) (rawClient, error) {
+	rawClientsLock.Lock()
+	defer rawClientsLock.Unlock()
+	raw, ok := rawClients[c]
+	if !ok {
+		return raw, errors.New("the API client was not set up by astartectl")
+	}
+	return raw, nil
+}
+
+// RawRequest is a client.AstarteRequest for the API calls astarte-go doesn't support yet. It is
+// run with the HTTP client and the credentials of the client it was built for, so it supports
+// --to-curl, --dry-run and the audit log like any other request.
+type RawRequest struct {
+	req     *http.Request
+	expects int
+}
+
+// RawResponse is the client.AstarteResponse of a RawRequest
+type RawResponse struct {
+	res *http.Response
+}
+
+// NewRawRequest builds a request to callURL for c, which must have been returned by APICommandSetup.
+// When payload is not nil, it is sent as the data of a JSON body with the given content type.
+// The request succeeds when Astarte replies with the expects status code.
+func NewRawRequest(c *client.Client, method string, callURL *url.URL, payload interface{}, contentType string, expects int) (client.AstarteRequest, error) {
+	raw, err := rawClientFor(c)
+	if err != nil {
+		return client.Empty{}, err
+	}
+	token, err := raw.credentials.jwt()
+	if err != nil {
+		return client.Empty{}, err
+	}
+
+	var body io.Reader
+	if payload != nil {
+		contents, err := json.Marshal(map[string]interface{}{"data": payload})
+		if err != nil {
+			return client.Empty{}, err
+		}
+		body = bytes.NewReader(contents)
+	}
+	req, err := http.NewRequest(method, callURL.String(), body)
+	if err != nil {
+		return client.Empty{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if payload != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Accept", "application/json")
+	return RawRequest{req: req, expects: expects}, nil
+}
+
+// Run implements client.AstarteRequest
+func (r RawRequest) Run(c *client.Client) (client.AstarteResponse, error) {
+	raw, err := rawClientFor(c)
+	if err != nil {
+		return client.Empty{}, err
+	}
+	res, err := raw.httpClient.Do(r.req)
+	if err != nil {
+		return client.Empty{}, err
+	}
+	if res.StatusCode != r.expects {
+		defer res.Body.Close()
+		return client.Empty{}, rawRequestError(res, r.expects)
+	}
+	return RawResponse{res: res}, nil
+}
+
+// ToCurl implements client.AstarteRequest
+func (r RawRequest) ToCurl(_ *client.Client) string {
+	command, _ := http2curl.GetCurlCommand(r.req)
+	return fmt.Sprint(command)
+}
+
+// Parse returns the data of the response as a json.RawMessage, which is nil when there is none
+func (r RawResponse) Parse() (any, error) {
+	defer r.res.Body.Close()
+	body := struct {
+		Data json.RawMessage `json:"data"`
+	}{}
+	contents, err := io.ReadAll(r.res.Body)
+	if err != nil || len(contents) == 0 {
+		return json.RawMessage(nil), err
+	}
+	if err := json.Unmarshal(contents, &body); err != nil {
+		return json.RawMessage(nil), err
+	}
+	return body.Data, nil
+}
+
+// Raw implements client.AstarteResponse
+func (r RawResponse) Raw(f func(*http.Response) any) any {
+	defer r.res.Body.Close()
+	return f(r.res)
+}
+
+// rawRequestError returns the errors reported by Astarte, as astarte-go does
+func rawRequestError(res *http.Response, expects int) error {
+	body := struct {
+		Errors map[string]interface{} `json:"errors"`
+	}{}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil || body.Errors == nil {
+		return fmt.Errorf("received unexpected status code %d instead of %d", res.StatusCode, expects)
+	}
+	errJSON, _ := json.MarshalIndent(&body, "", "  ")
+	return fmt.Errorf("%s", errJSON)
+}
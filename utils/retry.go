@@ -0,0 +1,192 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// defaultRetryBackoff is used when an invalid backoff has been configured
+const defaultRetryBackoff = 500 * time.Millisecond
+
+// retryTransport is an http.RoundTripper which retries requests failing with a transient error,
+// waiting an exponentially growing amount of time between attempts. Each attempt, reading the
+// response body included, is bounded by attemptTimeout.
+type retryTransport struct {
+	base           http.RoundTripper
+	maxAttempts    int
+	backoff        time.Duration
+	maxBackoff     time.Duration
+	attemptTimeout time.Duration
+}
+
+func newRetryTransport(base http.RoundTripper, maxAttempts int, backoff, maxBackoff, attemptTimeout time.Duration) http.RoundTripper {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	if backoff <= 0 {
+		backoff = defaultRetryBackoff
+	}
+	if maxBackoff < backoff {
+		maxBackoff = backoff
+	}
+	return &retryTransport{
+		base:           base,
+		maxAttempts:    maxAttempts,
+		backoff:        backoff,
+		maxBackoff:     maxBackoff,
+		attemptTimeout: attemptTimeout,
+	}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		ctx, cancel := t.attemptContext(req.Context())
+		attemptReq := req.WithContext(ctx)
+		if attempt > 1 && req.Body != nil && req.Body != http.NoBody {
+			// The body has been consumed by the previous attempt, rewind it
+			body, err := req.GetBody()
+			if err != nil {
+				cancel()
+				return nil, err
+			}
+			attemptReq = req.Clone(ctx)
+			attemptReq.Body = body
+		}
+
+		res, err := t.base.RoundTrip(attemptReq)
+		if attempt >= t.maxAttempts || !t.shouldRetry(req, res, err) {
+			return withCancel(res, err, cancel)
+		}
+
+		wait := t.backoffFor(attempt)
+		if res != nil {
+			if retryAfter, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+				if retryAfter > t.maxBackoff {
+					// The server asks us to wait longer than we are willing to, give up
+					return withCancel(res, err, cancel)
+				}
+				wait = retryAfter
+			}
+			// Drain the body to allow connection reuse
+			_, _ = io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+		}
+		cancel()
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// attemptContext returns the context of a single attempt, which expires after attemptTimeout
+func (t *retryTransport) attemptContext(parent context.Context) (context.Context, context.CancelFunc) {
+	if t.attemptTimeout <= 0 {
+		return context.WithCancel(parent)
+	}
+	return context.WithTimeout(parent, t.attemptTimeout)
+}
+
+// withCancel ties cancel to the lifetime of res, so that the attempt timeout keeps applying
+// while its body is read
+func withCancel(res *http.Response, err error, cancel context.CancelFunc) (*http.Response, error) {
+	if err != nil || res == nil {
+		cancel()
+		return res, err
+	}
+	res.Body = &cancelOnClose{ReadCloser: res.Body, cancel: cancel}
+	return res, nil
+}
+
+// cancelOnClose is a response body releasing the context of its attempt when closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+func (t *retryTransport) shouldRetry(req *http.Request, res *http.Response, err error) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		// We would not be able to send the body again
+		return false
+	}
+
+	if err != nil {
+		// Network errors might happen after the request reached the server, so retry
+		// them only when the request can be safely repeated
+		return req.Context().Err() == nil && isIdempotent(req.Method)
+	}
+
+	switch res.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		// The request was not processed
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		// The upstream might have processed the request before the proxy gave up on it
+		return isIdempotent(req.Method)
+	}
+	return false
+}
+
+// backoffFor returns the time to wait after the given attempt, doubling it at each attempt
+// and adding some jitter to avoid synchronized retries.
+func (t *retryTransport) backoffFor(attempt int) time.Duration {
+	wait := t.backoff
+	for i := 1; i < attempt && wait < t.maxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > t.maxBackoff {
+		wait = t.maxBackoff
+	}
+	return wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+}
+
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
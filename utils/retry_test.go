@@ -0,0 +1,225 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakyServer answers each request with the next status in statuses, and with 200 once they are
+// over. It records the bodies it receives.
+type flakyServer struct {
+	mu         sync.Mutex
+	statuses   []int
+	retryAfter string
+	bodies     []string
+}
+
+func (s *flakyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	body, _ := io.ReadAll(r.Body)
+	s.bodies = append(s.bodies, string(body))
+	if len(s.statuses) == 0 {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	status := s.statuses[0]
+	s.statuses = s.statuses[1:]
+	if s.retryAfter != "" {
+		w.Header().Set("Retry-After", s.retryAfter)
+	}
+	w.WriteHeader(status)
+}
+
+func (s *flakyServer) attempts() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.bodies)
+}
+
+func doRequest(t *testing.T, server *flakyServer, method, body string, maxBackoff time.Duration) *http.Response {
+	t.Helper()
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+
+	var reqBody io.Reader
+	if body != "" {
+		reqBody = strings.NewReader(body)
+	}
+	req, err := http.NewRequest(method, ts.URL, reqBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &http.Client{Transport: newRetryTransport(http.DefaultTransport, 3, time.Millisecond, maxBackoff, time.Second)}
+	res, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	return res
+}
+
+func TestRetryTransportStatuses(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		statuses     []int
+		wantStatus   int
+		wantAttempts int
+	}{
+		{"success", http.MethodGet, nil, http.StatusOK, 1},
+		{"transient errors", http.MethodGet, []int{http.StatusServiceUnavailable, http.StatusBadGateway}, http.StatusOK, 3},
+		{"max attempts", http.MethodGet, []int{503, 503, 503, 503}, http.StatusServiceUnavailable, 3},
+		{"client error", http.MethodGet, []int{http.StatusNotFound}, http.StatusNotFound, 1},
+		{"post too many requests", http.MethodPost, []int{http.StatusTooManyRequests}, http.StatusOK, 2},
+		{"post service unavailable", http.MethodPost, []int{http.StatusServiceUnavailable}, http.StatusOK, 2},
+		{"post bad gateway", http.MethodPost, []int{http.StatusBadGateway}, http.StatusBadGateway, 1},
+		{"post gateway timeout", http.MethodPost, []int{http.StatusGatewayTimeout}, http.StatusGatewayTimeout, 1},
+		{"patch bad gateway", http.MethodPatch, []int{http.StatusBadGateway}, http.StatusBadGateway, 1},
+		{"put bad gateway", http.MethodPut, []int{http.StatusBadGateway}, http.StatusOK, 2},
+		{"delete gateway timeout", http.MethodDelete, []int{http.StatusGatewayTimeout}, http.StatusOK, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &flakyServer{statuses: tt.statuses}
+			res := doRequest(t, server, tt.method, "", time.Second)
+			if res.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", res.StatusCode, tt.wantStatus)
+			}
+			if got := server.attempts(); got != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", got, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestRetryTransportRetryAfter(t *testing.T) {
+	server := &flakyServer{statuses: []int{http.StatusTooManyRequests}, retryAfter: "1"}
+	start := time.Now()
+	res := doRequest(t, server, http.MethodGet, "", 2*time.Second)
+	if res.StatusCode != http.StatusOK || server.attempts() != 2 {
+		t.Fatalf("status = %d after %d attempts, want 200 after 2", res.StatusCode, server.attempts())
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %s, before Retry-After", elapsed)
+	}
+
+	// Waiting longer than the maximum backoff is not worth it
+	server = &flakyServer{statuses: []int{http.StatusTooManyRequests}, retryAfter: "60"}
+	res = doRequest(t, server, http.MethodGet, "", time.Second)
+	if res.StatusCode != http.StatusTooManyRequests || server.attempts() != 1 {
+		t.Errorf("status = %d after %d attempts, want 429 after 1", res.StatusCode, server.attempts())
+	}
+}
+
+func TestRetryTransportReplaysBody(t *testing.T) {
+	const body = `{"data":{"value":42}}`
+	server := &flakyServer{statuses: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}}
+	res := doRequest(t, server, http.MethodPost, body, time.Second)
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", res.StatusCode)
+	}
+	if len(server.bodies) != 3 {
+		t.Fatalf("attempts = %d, want 3", len(server.bodies))
+	}
+	for i, b := range server.bodies {
+		if b != body {
+			t.Errorf("body of attempt %d = %q, want %q", i+1, b, body)
+		}
+	}
+}
+
+func TestRetryTransportAttemptTimeout(t *testing.T) {
+	var mu sync.Mutex
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		attempts++
+		slow := attempts == 1
+		mu.Unlock()
+		if slow {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+			return
+		}
+		_, _ = io.WriteString(w, "ok")
+	}))
+	t.Cleanup(ts.Close)
+
+	// The first attempt times out, the second one gets its own time budget
+	c := &http.Client{Transport: newRetryTransport(http.DefaultTransport, 2, time.Millisecond, time.Millisecond, 100*time.Millisecond)}
+	res, err := c.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if string(body) != "ok" || attempts != 2 {
+		t.Errorf("body = %q after %d attempts, want \"ok\" after 2", body, attempts)
+	}
+
+}
+
+func TestRetryTransportTimeoutWithoutRetries(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	c := &http.Client{Transport: newRetryTransport(http.DefaultTransport, 1, time.Millisecond, time.Millisecond, 100*time.Millisecond)}
+	if res, err := c.Get(ts.URL); err == nil {
+		res.Body.Close()
+		t.Error("request did not time out")
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"", 0, false},
+		{"3", 3 * time.Second, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{"Mon, 02 Jan 2006 15:04:05 GMT", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("parseRetryAfter(%q) = %s, %v, want %s, %v", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
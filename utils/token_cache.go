@@ -0,0 +1,141 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/astarte-platform/astarte-go/astarteservices"
+	"github.com/astarte-platform/astarte-go/auth"
+	"github.com/astarte-platform/astartectl/config"
+)
+
+// cachedTokenTTL is the validity of the tokens minted for the token cache
+const cachedTokenTTL = 5 * time.Minute
+
+// RealmServices are the APIs a realm key grants access to
+var RealmServices = []astarteservices.AstarteService{
+	astarteservices.AppEngine,
+	astarteservices.Channels,
+	astarteservices.Flow,
+	astarteservices.Pairing,
+	astarteservices.RealmManagement,
+}
+
+// CachedToken returns a token signed with privateKey granting full access to services. Tokens are cached
+// on disk, keyed by context, key and services, and are reused by later invocations until they expire.
+func CachedToken(contextName string, privateKey []byte, services []astarteservices.AstarteService) (string, error) {
+	cachePath := tokenCachePath(contextName, privateKey, services)
+	if cached, err := loadCachedCredential(cachePath); err == nil {
+		return cached.Token, nil
+	}
+
+	servicesAndClaims := map[astarteservices.AstarteService][]string{}
+	for _, s := range services {
+		servicesAndClaims[s] = []string{}
+	}
+	token, err := auth.GenerateAstarteJWTFromPEMKey(privateKey, servicesAndClaims, int64(cachedTokenTTL.Seconds()))
+	if err != nil {
+		return "", err
+	}
+
+	expiration := time.Now().Add(cachedTokenTTL)
+	// Failing to cache the token is not fatal, a new one will be minted next time
+	_ = saveCachedCredential(cachePath, execCredential{Token: token, Expiration: &expiration})
+	return token, nil
+}
+
+// ContextToken returns a token for the given services, using the credentials of a context. Housekeeping
+// credentials are taken from the context's cluster, and can't be mixed with other services.
+func ContextToken(contextName string, services []astarteservices.AstarteService) (string, error) {
+	configDir := config.GetConfigDir()
+	context, err := config.LoadContextConfiguration(configDir, contextName)
+	if err != nil {
+		return "", err
+	}
+
+	key, token := context.Realm.Key, context.Realm.Token
+	tokenCommand, keyCommand := context.Realm.TokenCommand, context.Realm.KeyCommand
+	for _, s := range services {
+		if s != astarteservices.Housekeeping {
+			continue
+		}
+		if len(services) != 1 {
+			return "", errors.New("housekeeping can't be requested together with other services")
+		}
+		cluster, err := config.LoadClusterConfiguration(configDir, context.Cluster)
+		if err != nil {
+			return "", err
+		}
+		key, token = cluster.Housekeeping.Key, cluster.Housekeeping.Token
+		tokenCommand, keyCommand = cluster.Housekeeping.TokenCommand, cluster.Housekeeping.KeyCommand
+	}
+
+	var privateKey []byte
+	switch {
+	case token != "":
+		return token, nil
+	case key != "":
+		if privateKey, err = config.DecodeKey(key); err != nil {
+			return "", err
+		}
+	case tokenCommand != nil:
+		return tokenFromCommand(tokenCommand)
+	case keyCommand != nil:
+		if privateKey, err = keyFromCommand(keyCommand); err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("context %s has no credentials for the requested services", contextName)
+	}
+
+	return CachedToken(contextName, privateKey, services)
+}
+
+// tokenCachePath returns a cache file path unique to the context, the key and the set of services
+func tokenCachePath(contextName string, privateKey []byte, services []astarteservices.AstarteService) string {
+	serviceNames := make([]string, 0, len(services))
+	for _, s := range services {
+		serviceNames = append(serviceNames, s.String())
+	}
+	sort.Strings(serviceNames)
+
+	h := sha256.New()
+	h.Write(privateKey)
+	for _, s := range serviceNames {
+		fmt.Fprintln(h, s)
+	}
+	// The key is part of the hash, so that tokens are never shared between different keys
+	fileName := hex.EncodeToString(h.Sum(nil)) + ".json"
+	if contextName != "" {
+		fileName = contextName + "-" + fileName
+	}
+	return path.Join(config.GetCacheDir(), "tokens", fileName)
+}
+
+func includesHousekeeping(services []astarteservices.AstarteService) bool {
+	for _, s := range services {
+		if s == astarteservices.Housekeeping {
+			return true
+		}
+	}
+	return false
+}
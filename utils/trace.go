@@ -0,0 +1,180 @@
+// Copyright © 2026 SECO Mind Srl
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	redactedValue = "<redacted>"
+	// maxTracedBodySize is the maximum number of bytes of a body that will be logged
+	maxTracedBodySize = 64 * 1024
+)
+
+// redactedHeaders are headers whose value is never logged
+var redactedHeaders = map[string]bool{
+	"Authorization": true,
+	"Cookie":        true,
+	"Set-Cookie":    true,
+}
+
+// redactedFields are JSON fields whose value is never logged, at any depth of a body
+var redactedFields = map[string]bool{
+	"jwt_public_key_pem": true,
+	"private_key":        true,
+	"key":                true,
+	"credentials_secret": true,
+	"secret":             true,
+	"token":              true,
+}
+
+// tracingTransport is an http.RoundTripper logging requests and responses to stderr.
+// Headers and bodies are logged only when withBodies is true. Secrets are always redacted.
+type tracingTransport struct {
+	base       http.RoundTripper
+	withBodies bool
+	out        io.Writer
+}
+
+func newTracingTransport(base http.RoundTripper, withBodies bool) http.RoundTripper {
+	return &tracingTransport{
+		base:       base,
+		withBodies: withBodies,
+		out:        os.Stderr,
+	}
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "--> %s %s\n", req.Method, req.URL.Redacted())
+	if t.withBodies {
+		writeHeaders(&b, req.Header)
+		if req.Body != nil && req.Body != http.NoBody && req.GetBody != nil {
+			// Read a copy of the body, so that the one sent is left untouched
+			if body, err := req.GetBody(); err == nil {
+				writeBody(&b, body)
+			}
+		}
+	}
+	fmt.Fprint(t.out, b.String())
+
+	start := time.Now()
+	res, err := t.base.RoundTrip(req)
+	latency := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		fmt.Fprintf(t.out, "<-- %s %s failed after %s: %s\n", req.Method, req.URL.Redacted(), latency, err)
+		return res, err
+	}
+
+	b.Reset()
+	fmt.Fprintf(&b, "<-- %s %s %s (%s)\n", res.Status, req.Method, req.URL.Redacted(), latency)
+	if t.withBodies {
+		writeHeaders(&b, res.Header)
+		body, readErr := io.ReadAll(res.Body)
+		res.Body.Close()
+		res.Body = io.NopCloser(bytes.NewReader(body))
+		writeBody(&b, io.NopCloser(bytes.NewReader(body)))
+		if readErr != nil {
+			fmt.Fprintf(&b, "    (error while reading body: %s)\n", readErr)
+		}
+	}
+	fmt.Fprint(t.out, b.String())
+
+	return res, err
+}
+
+func writeHeaders(w io.Writer, header http.Header) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range header[name] {
+			fmt.Fprintf(w, "    %s: %s\n", name, redactHeader(name, value))
+		}
+	}
+}
+
+func redactHeader(name, value string) string {
+	if !redactedHeaders[http.CanonicalHeaderKey(name)] {
+		return value
+	}
+	// Keep the scheme, it helps understanding which kind of auth was used
+	if scheme, _, found := strings.Cut(value, " "); found {
+		return scheme + " " + redactedValue
+	}
+	return redactedValue
+}
+
+func writeBody(w io.Writer, body io.ReadCloser) {
+	defer body.Close()
+	contents, err := io.ReadAll(io.LimitReader(body, maxTracedBodySize+1))
+	if err != nil || len(contents) == 0 {
+		return
+	}
+	truncated := len(contents) > maxTracedBodySize
+	if truncated {
+		contents = contents[:maxTracedBodySize]
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%s\n", redactBody(contents))
+	if truncated {
+		fmt.Fprintln(w, "    (body truncated)")
+	}
+	fmt.Fprintln(w)
+}
+
+// redactBody hides the value of sensitive fields in JSON bodies. Bodies which are not valid
+// JSON (including truncated ones) are hidden altogether, as they can't be inspected reliably.
+func redactBody(contents []byte) string {
+	var decoded interface{}
+	if err := json.Unmarshal(contents, &decoded); err != nil {
+		return fmt.Sprintf("    (%d bytes of non-JSON body)", len(contents))
+	}
+	redacted, err := json.MarshalIndent(redactJSON(decoded), "    ", "  ")
+	if err != nil {
+		return fmt.Sprintf("    (%d bytes of body)", len(contents))
+	}
+	return "    " + string(redacted)
+}
+
+func redactJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for field, fieldValue := range v {
+			if redactedFields[field] {
+				v[field] = redactedValue
+			} else {
+				v[field] = redactJSON(fieldValue)
+			}
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redactJSON(v[i])
+		}
+	}
+	return value
+}